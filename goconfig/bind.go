@@ -0,0 +1,133 @@
+package goconfig
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	tagEnv     = "env"
+	tagDefault = "default"
+	tagFlag    = "flag"
+)
+
+func (g goConfig) BindEnv(structure interface{}) error {
+	value, err := structValue(structure)
+	if err != nil {
+		return err
+	}
+
+	return walkFields(value, "", bindEnvField)
+}
+
+// bindEnvField sets the field from the environment variable named by its env tag,
+// falling back to its default tag when the variable is empty.
+func bindEnvField(field reflect.Value, structField reflect.StructField, path string) error {
+	key, ok := structField.Tag.Lookup(tagEnv)
+	if !ok {
+		return nil
+	}
+
+	raw := os.Getenv(key)
+	if raw == "" {
+		if raw, ok = structField.Tag.Lookup(tagDefault); !ok {
+			return nil
+		}
+	}
+
+	if err := setValueFromString(field, raw); err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrConvertingValue, path, err)
+	}
+
+	return nil
+}
+
+// GetEnvAs returns the environment variable converted to the type T, e.g. GetEnvAs("PORT", 8080).
+// It returns the default value if the variable is not set, empty or cannot be converted.
+// Supported types are string, bool, integers, floats, time.Duration and pointers to them.
+func GetEnvAs[T any](key string, def T) T {
+	value, err := LookupEnvAs[T](key)
+	if err != nil {
+		return def
+	}
+
+	return value
+}
+
+// RequireEnv checks that the environment variables are set, e.g. at startup to catch a deployment missing some of them.
+// A variable set to an empty string is set. It returns an error wrapping ErrVariableNotFound listing all the
+// variables not set, and sets nothing.
+func RequireEnv(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if _, ok := os.LookupEnv(key); !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf(formatError, ErrVariableNotFound, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// LookupEnvAs returns the environment variable converted to the type T.
+// It returns an error wrapping ErrVariableNotFound if the variable is not set or empty,
+// and an error wrapping ErrConvertingValue if it cannot be converted.
+func LookupEnvAs[T any](key string) (T, error) {
+	var value T
+	raw := os.Getenv(key)
+	if raw == "" {
+		return value, fmt.Errorf(formatError, ErrVariableNotFound, key)
+	}
+
+	if err := setValueFromString(reflect.ValueOf(&value).Elem(), raw); err != nil {
+		return value, fmt.Errorf("%w: %v: %w", ErrConvertingValue, key, err)
+	}
+
+	return value, nil
+}
+
+func (g goConfig) BindFlags(structure interface{}, flags *flag.FlagSet) error {
+	overrides := make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		overrides[f.Name] = f.Value.String()
+	})
+
+	return g.BindOverrides(structure, overrides)
+}
+
+func (g goConfig) BindOverrides(structure interface{}, overrides map[string]string) error {
+	value, err := structValue(structure)
+	if err != nil {
+		return err
+	}
+
+	return walkFields(value, "", func(field reflect.Value, structField reflect.StructField, path string) error {
+		return bindOverrideField(field, structField, path, overrides)
+	})
+}
+
+// bindOverrideField sets the field from the override named by its flag tag, if present.
+func bindOverrideField(field reflect.Value, structField reflect.StructField, path string,
+	overrides map[string]string) error {
+	name, ok := structField.Tag.Lookup(tagFlag)
+	if !ok {
+		return nil
+	}
+
+	raw, ok := overrides[name]
+	if !ok {
+		return nil
+	}
+
+	if err := setValueFromString(field, raw); err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrConvertingValue, path, err)
+	}
+
+	return nil
+}
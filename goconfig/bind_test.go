@@ -0,0 +1,185 @@
+package goconfig_test
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type EnvConfig struct {
+	Name     string        `env:"APP_NAME"`
+	Port     int           `env:"APP_PORT" default:"8080"`
+	Debug    bool          `env:"APP_DEBUG"`
+	Ratio    float64       `env:"APP_RATIO"`
+	Timeout  time.Duration `env:"APP_TIMEOUT" default:"5s"`
+	Replicas *uint         `env:"APP_REPLICAS"`
+	Untagged string
+	Storage  struct {
+		Host string `env:"DB_HOST"`
+	}
+}
+
+func TestBindEnvSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	t.Setenv("APP_DEBUG", "true")
+	t.Setenv("APP_RATIO", "0.5")
+	t.Setenv("APP_REPLICAS", "3")
+	t.Setenv("DB_HOST", "master-pg.localhost")
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	envCfg := EnvConfig{Untagged: "unchanged"}
+	err := config.BindEnv(&envCfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TestApp", envCfg.Name)
+	assert.Equal(t, 8080, envCfg.Port)
+	assert.True(t, envCfg.Debug)
+	assert.Equal(t, 0.5, envCfg.Ratio)
+	assert.Equal(t, 5*time.Second, envCfg.Timeout)
+	assert.Equal(t, uint(3), *envCfg.Replicas)
+	assert.Equal(t, "unchanged", envCfg.Untagged)
+	assert.Equal(t, "master-pg.localhost", envCfg.Storage.Host)
+}
+
+func TestBindEnvSuccessMissingVariablesUnchanged(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	envCfg := EnvConfig{Name: "DefaultApp"}
+	err := config.BindEnv(&envCfg)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "DefaultApp", envCfg.Name)
+	assert.Nil(t, envCfg.Replicas)
+}
+
+func TestBindEnvFailConvertingValue(t *testing.T) {
+	t.Setenv("APP_PORT", "not-a-number")
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var envCfg EnvConfig
+	err := config.BindEnv(&envCfg)
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.Contains(t, err.Error(), "Port")
+}
+
+func TestBindEnvFailInvalidStructure(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var envCfg EnvConfig
+	err := config.BindEnv(envCfg)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+}
+
+type FlagConfig struct {
+	Server struct {
+		Host string `yaml:"host" env:"SERVER_HOST" flag:"host"`
+		Port int    `yaml:"port" env:"SERVER_PORT" flag:"port"`
+	} `yaml:"server"`
+	Debug bool `yaml:"debug" flag:"debug"`
+}
+
+func TestBindFlagsSuccess(t *testing.T) {
+	dir, _ := createConfigFile(t, "server:\n  host: file-host\n  port: 8080\ndebug: true\n")
+	t.Setenv("SERVER_HOST", "env-host")
+	t.Setenv("SERVER_PORT", "8081")
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	flags.String("host", "flag-default-host", "server host")
+	flags.Int("port", 0, "server port")
+	flags.Bool("debug", false, "debug mode")
+	err := flags.Parse([]string{"--port", "9090"})
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig()
+	var flagCfg FlagConfig
+	assert.NoError(t, config.ParseConfig(&flagCfg, "App", dir))
+	assert.NoError(t, config.BindEnv(&flagCfg))
+	assert.NoError(t, config.BindFlags(&flagCfg, flags))
+
+	assert.Equal(t, "env-host", flagCfg.Server.Host)
+	assert.Equal(t, 9090, flagCfg.Server.Port)
+	assert.True(t, flagCfg.Debug)
+}
+
+func TestBindOverridesSuccess(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	flagCfg := FlagConfig{Debug: true}
+	err := config.BindOverrides(&flagCfg, map[string]string{"host": "localhost", "debug": "false", "unknown": "x"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "localhost", flagCfg.Server.Host)
+	assert.Zero(t, flagCfg.Server.Port)
+	assert.False(t, flagCfg.Debug)
+}
+
+func TestBindOverridesFailConvertingValue(t *testing.T) {
+	var flagCfg FlagConfig
+	err := goconfig.NewGoConfig().BindOverrides(&flagCfg, map[string]string{"port": "eighty"})
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "Server.Port")
+}
+
+func TestBindFlagsFailInvalidStructure(t *testing.T) {
+	err := goconfig.NewGoConfig().BindFlags(FlagConfig{}, flag.NewFlagSet("app", flag.ContinueOnError))
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+}
+
+func TestGetEnvAs(t *testing.T) {
+	t.Setenv("TYPED_PORT", "5432")
+	t.Setenv("TYPED_DEBUG", "true")
+	t.Setenv("TYPED_TIMEOUT", "30s")
+	t.Setenv("TYPED_RATIO", "0.5")
+	t.Setenv("TYPED_NAME", "AppName")
+	t.Setenv("TYPED_INVALID", "not a number")
+
+	assert.Equal(t, 5432, goconfig.GetEnvAs("TYPED_PORT", 8080))
+	assert.Equal(t, uint16(5432), goconfig.GetEnvAs[uint16]("TYPED_PORT", 0))
+	assert.True(t, goconfig.GetEnvAs("TYPED_DEBUG", false))
+	assert.Equal(t, 30*time.Second, goconfig.GetEnvAs("TYPED_TIMEOUT", time.Second))
+	assert.Equal(t, 0.5, goconfig.GetEnvAs("TYPED_RATIO", 1.0))
+	assert.Equal(t, "AppName", goconfig.GetEnvAs("TYPED_NAME", "Default"))
+	assert.Equal(t, 8080, goconfig.GetEnvAs("TYPED_MISSING", 8080))
+	assert.Equal(t, 8080, goconfig.GetEnvAs("TYPED_INVALID", 8080))
+	assert.False(t, goconfig.GetEnvAs("TYPED_INVALID", false))
+	assert.Equal(t, time.Second, goconfig.GetEnvAs("TYPED_INVALID", time.Second))
+}
+
+func TestRequireEnv(t *testing.T) {
+	t.Setenv("REQUIRED_HOST", "localhost")
+	t.Setenv("REQUIRED_EMPTY", "")
+
+	err := goconfig.RequireEnv("REQUIRED_HOST", "REQUIRED_USER", "REQUIRED_EMPTY", "REQUIRED_PASSWORD")
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.EqualError(t, err, "environment variable not found: REQUIRED_USER, REQUIRED_PASSWORD")
+
+	assert.NoError(t, goconfig.RequireEnv("REQUIRED_HOST", "REQUIRED_EMPTY"))
+	assert.NoError(t, goconfig.RequireEnv())
+}
+
+func TestLookupEnvAs(t *testing.T) {
+	t.Setenv("TYPED_PORT", "5432")
+	t.Setenv("TYPED_INVALID", "not a number")
+
+	port, err := goconfig.LookupEnvAs[*int]("TYPED_PORT")
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, *port)
+
+	_, err = goconfig.LookupEnvAs[int]("TYPED_MISSING")
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+
+	_, err = goconfig.LookupEnvAs[int]("TYPED_INVALID")
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "TYPED_INVALID")
+
+	_, err = goconfig.LookupEnvAs[[]string]("TYPED_PORT")
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+}
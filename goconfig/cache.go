@@ -0,0 +1,114 @@
+package goconfig
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// configCache stores the last configuration successfully parsed by ParseConfig, it is safe for concurrent use.
+type configCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]reflect.Value
+}
+
+// cacheKey identifies a cached configuration by its file name, directories and structure type.
+type cacheKey struct {
+	fileName      string
+	directories   string
+	structureType reflect.Type
+}
+
+// newConfigCache creates an empty configuration cache.
+func newConfigCache() *configCache {
+	return &configCache{entries: make(map[cacheKey]reflect.Value)}
+}
+
+// newCacheKey creates the key of the configuration file in the directories parsed into the structure type.
+func newCacheKey(fileName string, directories []string, structureType reflect.Type) cacheKey {
+	return cacheKey{fileName: fileName, directories: strings.Join(directories, "\x00"), structureType: structureType}
+}
+
+// get returns a deep copy of the cached configuration of the key, so the caller cannot change the cached one.
+func (c *configCache) get(key cacheKey) (reflect.Value, bool) {
+	c.mu.Lock()
+	value, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if !ok {
+		return value, false
+	}
+
+	return deepCopy(value), true
+}
+
+// set stores a deep copy of the configuration for the key.
+func (c *configCache) set(key cacheKey, value reflect.Value) {
+	stored := deepCopy(value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = stored
+}
+
+// keys returns the keys cached for the configuration file in the directories, whatever their structure type.
+func (c *configCache) keys(fileName string, directories []string) []cacheKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []cacheKey
+	for key := range c.entries {
+		if key == newCacheKey(fileName, directories, key.structureType) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (g goConfig) Reload(fileName string, directoryName ...string) error {
+	if g.cache == nil {
+		return nil
+	}
+
+	fileName = g.configName(fileName)
+	for _, key := range g.cache.keys(fileName, g.configDirs(directoryName)) {
+		if err := g.parseFresh(reflect.New(key.structureType).Interface(), fileName, directoryName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseCached parses the configuration like ParseConfigFS from the OS filesystem,
+// returning the cached configuration when the same file was already parsed into the same structure type.
+func (g goConfig) parseCached(structure interface{}, fileName string, directoryName []string) error {
+	value := reflect.ValueOf(structure)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return g.ParseConfigFS(g.osFS(), structure, fileName, directoryName...)
+	}
+
+	if cached, ok := g.cache.get(newCacheKey(fileName, g.configDirs(directoryName), value.Type().Elem())); ok {
+		value.Elem().Set(cached)
+		return nil
+	}
+
+	return g.parseFresh(structure, fileName, directoryName)
+}
+
+// parseFresh parses the configuration like ParseConfigFS from the OS filesystem, ignoring the cache,
+// and stores the result in the cache, if enabled.
+func (g goConfig) parseFresh(structure interface{}, fileName string, directoryName []string) error {
+	if err := g.ParseConfigFS(g.osFS(), structure, fileName, directoryName...); err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(structure)
+	if g.cache != nil && value.Kind() == reflect.Pointer && !value.IsNil() {
+		g.cache.set(newCacheKey(fileName, g.configDirs(directoryName), value.Type().Elem()), value.Elem())
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCache(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig(goconfig.WithCache())
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte("App:\n  name: NewName\n"), 0644)
+	assert.NoError(t, err)
+
+	var cachedCfg AppConfig
+	err = config.ParseConfig(&cachedCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", cachedCfg.App.Name)
+
+	err = config.Reload("App", dir)
+	assert.NoError(t, err)
+
+	var reloadedCfg AppConfig
+	err = config.ParseConfig(&reloadedCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "NewName", reloadedCfg.App.Name)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestWithCacheKeyedByStructureType(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig(goconfig.WithCache())
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+
+	values := map[string]interface{}{}
+	err = config.ParseConfig(&values, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "AppName"}, values["App"])
+}
+
+func TestWithCacheReturnsCopies(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n  tags: [a, b]\n")
+	config := goconfig.NewGoConfig(goconfig.WithCache())
+
+	values := map[string]interface{}{}
+	err := config.ParseConfig(&values, "App", dir)
+	assert.NoError(t, err)
+	values["App"].(map[string]interface{})["name"] = "Changed"
+	values["App"].(map[string]interface{})["tags"].([]interface{})[0] = "z"
+
+	cached := map[string]interface{}{}
+	err = config.ParseConfig(&cached, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "AppName", "tags": []interface{}{"a", "b"}}, cached["App"])
+
+	cached["App"].(map[string]interface{})["name"] = "Other"
+
+	again := map[string]interface{}{}
+	err = config.ParseConfig(&again, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", again["App"].(map[string]interface{})["name"])
+}
+
+func TestWithCacheDoesNotCacheErrors(t *testing.T) {
+	dir := t.TempDir()
+	config := goconfig.NewGoConfig(goconfig.WithCache())
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+
+	err = os.WriteFile(filepath.Join(dir, "App.yaml"), []byte("App:\n  name: AppName\n"), 0644)
+	assert.NoError(t, err)
+
+	err = config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestWithCacheConcurrentParse(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig(goconfig.WithCache())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var yamlCfg AppConfig
+			assert.NoError(t, config.ParseConfig(&yamlCfg, "App", dir))
+			assert.Equal(t, "AppName", yamlCfg.App.Name)
+			assert.NoError(t, config.Reload("App", dir))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestReloadWithoutCache(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte("App:\n  name: NewName\n"), 0644)
+	assert.NoError(t, err)
+	assert.NoError(t, config.Reload("App", dir))
+
+	err = config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "NewName", yamlCfg.App.Name)
+}
@@ -1,202 +1,1285 @@
 package goconfig
 
 import (
-	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
-	"regexp"
+	"path/filepath"
+	"reflect"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
 var (
-	excludeExtensions = []string{"go"}
-	regexEnv          = regexp.MustCompile(`\${(\w+)}`)
-	regexEnvFromFile  = regexp.MustCompile(`^\s*([\w.-]+)\s*=\s*(.*)?\s*$`)
+	defaultExcludeExtensions  = []string{"go"}
+	defaultEnvCommentPrefixes = []string{"#"}
+	// utf8BOM is the byte order mark starting some UTF-8 files, e.g. saved by Windows editors.
+	utf8BOM = []byte("\xEF\xBB\xBF")
 )
 
 const (
 	formatError = "%w: %v"
+	// defaultProfileEnvVar is the environment variable holding the profile name.
+	defaultProfileEnvVar = "APP_ENV"
+	// stdinPath is the file path read from the standard input by ParseConfigFile.
+	stdinPath = "-"
+	// defaultExtension is the extension of the parser used when the content has no file extension.
+	defaultExtension = "yaml"
+	// defaultConfigDir is the directory of the configuration files when no directory is provided.
+	defaultConfigDir = "config"
+	// defaultEnvFile is the .env file loaded when no file is provided.
+	defaultEnvFile = ".env"
 )
 
+// configFile is a configuration file read from a directory.
+type configFile struct {
+	path      string
+	extension string
+	content   []byte
+}
+
 // goConfig is the GoConfig implementation.
 type goConfig struct {
-	unmarshallFunc func(interface{}, []byte) error
+	unmarshallFunc     func(interface{}, []byte, string) error
+	parsers            *parserRegistry
+	excludeExtensions  []string
+	allowedExtensions  []string
+	validate           bool
+	defaults           bool
+	caseSensitive      bool
+	logger             Logger
+	cache              *configCache
+	envAggregateErrors bool
+	envCommentPrefixes []string
+	baseDir            string
+	hooks              *hookRegistry
+	defaultDir         string
+	defaultEnvFile     string
+	profileEnvVar      string
+	profileFromEnv     bool
+	defaultProfile     string
+	envSyntax          envSyntax
+	redactSecrets      bool
+	envCascadeVar      string
+	envCascade         []string
+	loadedEnv          *loadedEnv
+	encodedValues      bool
+	concatYAML         bool
+	resolver           func(key string) (string, bool)
+	noSubstitution     bool
+	envBareKeys        BareKeyPolicy
+	sliceMerge         SliceMergeStrategy
+	envOverrides       bool
+	envOverridePrefix  string
+	strictPermissions  bool
+	envExpansion       bool
+	sources            *sourceTracker
+	format             string
+	envIncludes        bool
+	httpTimeout        time.Duration
+	template           bool
+	envDuplicateKeys   DuplicateKeyPolicy
+	ctx                context.Context
+}
+
+// osFS returns the filesystem of the operating system resolving relative paths against the base directory.
+func (g goConfig) osFS() osFS {
+	return osFS{baseDir: g.baseDir}
 }
 
 // GoConfig is the interface that wraps the Read, LoadEnv and Unmarshall methods.
+// Its methods are safe for concurrent use: the .env files are loaded one at a time and the registered parsers
+// are guarded, the logger and the unmarshalling functions provided must be safe for concurrent use too.
 type GoConfig interface {
 	// LoadEnv loads environment variables from a .env files.
-	// If no files are provided, it will use the default file ".env".
+	// If no files are provided, it will use the default file ".env", or the one set with WithDefaultEnvFile.
 	LoadEnv(envFiles ...string) error
+	// MustLoadEnv works like LoadEnv but panics on error, for small programs and tests.
+	MustLoadEnv(envFiles ...string)
+	// LoadEnvIfAbsent loads environment variables from .env files like LoadEnv,
+	// but skips the variables already present in the environment, so the real environment takes precedence.
+	LoadEnvIfAbsent(envFiles ...string) error
+	// LoadEnvCascade loads the .env files of the cascade in order, later files overriding earlier ones:
+	// .env, .env.<APP_ENV>, .env.local and .env.<APP_ENV>.local. Missing files are skipped, as well as the profile
+	// specific files when APP_ENV is not set. The .env file is the one set with WithDefaultEnvFile, the variable
+	// and the cascade are set with WithEnvCascade.
+	LoadEnvCascade() error
+	// UnloadEnv removes the environment variables set by LoadEnv, LoadEnvIfAbsent and LoadEnvCascade,
+	// restoring the values they had before being loaded, e.g. to clean up after a test.
+	UnloadEnv() error
+	// ParseEnv parses .env files like LoadEnv and returns the variables found without setting them. On error, the
+	// map is nil, unless WithEnvAggregateErrors is used: the variables of the valid lines are returned with the error.
+	ParseEnv(envFiles ...string) (map[string]string, error)
 	// ParseConfig reads a configuration file from a directory and unmarshalls it into a structure.
-	// If no directory is provided, it will use the default directory "config".
+	// The structure must be a non-nil pointer to a struct or a map, e.g. *map[string]interface{} to parse the file
+	// without a schema, otherwise an error wrapping ErrInvalidStructure is returned. Defaults and validation only
+	// apply to structs.
+	// If no directory is provided, it will use the default directory "config", or the one set with WithDefaultDir.
+	// If the file name is empty and WithProfileFromEnv is used, the file is named after the profile.
+	// If several directories are provided, the file is read from each of them and the results are deep-merged,
+	// later directories taking precedence: structs and maps are merged key by key, while other values,
+	// slices included, are replaced when the key is present, even by the zero value, e.g. "debug: false". With a
+	// custom unmarshaller or parser, or files whose formats use different struct tags, e.g. YAML and JSON, the
+	// results are merged field by field instead and the zero values are ignored.
+	// A file can list other files under a top-level "include" key, e.g. include: [base.yaml, secrets.yaml], with
+	// paths relative to its directory. They are deep-merged in order before the file, which takes precedence,
+	// and a file including itself returns an error wrapping ErrCircularInclude. The include key is removed before
+	// unmarshalling. It is also resolved by ParseConfigProfile, ParseConfigDir, ParseConfigRecursive and
+	// ParseConfigDocument, but not by ParseConfigFile nor the methods parsing bytes or readers.
 	ParseConfig(structure interface{}, fileName string, directoryName ...string) error
+	// MustParseConfig works like ParseConfig but panics on error, for small programs and tests.
+	MustParseConfig(structure interface{}, fileName string, directoryName ...string)
+	// Validate checks the configuration file like ParseConfig would parse it, without modifying the cache nor the
+	// sources: every ${VAR} reference must resolve and the file must parse. It is meant for CI or a "config check"
+	// command.
+	Validate(fileName string, directoryName ...string) error
+	// ValidateConfig works like Validate but also checks the file parsed into a new value of the structure type,
+	// leaving the structure untouched: the fields tagged with `validate:"required"` must be set, even without
+	// WithValidation. If the structure is nil, it works like Validate.
+	ValidateConfig(structure interface{}, fileName string, directoryName ...string) error
+	// Marshal serializes the structure as YAML, e.g. to log the effective configuration after merging, substituting
+	// the environment variables and applying the defaults. With WithRedactedSecrets, the fields tagged with
+	// `secret:"true"` are masked in the output, the structure is not modified.
+	Marshal(structure interface{}) ([]byte, error)
+	// ParseConfigContext works like ParseConfig but checks the context before scanning each directory and reading
+	// each file, including the included files, and stops when it is done, returning an error wrapping
+	// ErrReadingFile and the context error. The structure is only updated when parsing succeeds, the fields already
+	// filled in being kept like with ParseConfig.
+	ParseConfigContext(ctx context.Context, structure interface{}, fileName string, directoryName ...string) error
+	// ParseConfigAs works like ParseConfig but parses the file with the parser of the format, e.g. "yaml", instead of
+	// inferring it from the extension. The file is matched by its whole name, e.g. a mounted "config" file without
+	// extension, or by its name without extension, whatever the extension, e.g. "config.conf". It returns an error
+	// wrapping ErrUnsupportedExt if no parser is registered for the format. The cache is not used.
+	ParseConfigAs(structure interface{}, format, fileName string, directoryName ...string) error
+	// ParseConfigWithPaths works like ParseConfig and returns the absolute paths of the files read,
+	// in merge order with the included files before the file including them, to diagnose which files were loaded.
+	ParseConfigWithPaths(structure interface{}, fileName string, directoryName ...string) ([]string, error)
+	// Reload parses again the configuration file cached by ParseConfig when the cache is enabled with WithCache,
+	// so the next calls with the same file name and directories return the fresh configuration.
+	// It does nothing if the cache is not enabled or the configuration was not parsed yet.
+	Reload(fileName string, directoryName ...string) error
+	// ParseValues works like ParseConfig but parses the configuration without a structure,
+	// its values are accessed with dotted paths, e.g. values.GetInt("storage.master.port").
+	ParseValues(fileName string, directoryName ...string) (Values, error)
+	// ParseConfigNode reads a YAML or JSON configuration file like ParseConfig, from the first directory only, and
+	// returns its document node, which keeps the order of the keys and the comments, so Marshal reproduces them,
+	// e.g. to re-emit the configuration for a human to read. Other formats return an error wrapping ErrUnsupportedExt.
+	ParseConfigNode(fileName string, directoryName ...string) (*yaml.Node, error)
+	// ParseConfigDocument reads a YAML configuration file holding several documents separated by "---", e.g. Kubernetes
+	// manifests, like ParseConfig, from the first directory only, and unmarshalls the document at the index, starting
+	// at 0, into a structure. An index out of range returns an error wrapping ErrDocumentNotFound and other formats an
+	// error wrapping ErrUnsupportedExt. The environment variables are only substituted in the document parsed.
+	ParseConfigDocument(structure interface{}, fileName string, index int, directoryName ...string) error
+	// ParseConfigDocumentByName works like ParseConfigDocument but unmarshalls the first document whose top-level
+	// "name" key is the name, e.g. "name: staging". The key is unmarshalled like the others. If no document has the
+	// name, it returns an error wrapping ErrDocumentNotFound.
+	ParseConfigDocumentByName(structure interface{}, fileName, name string, directoryName ...string) error
+	// Sources returns the source of every value of the last configuration parsed when source tracking is enabled with
+	// WithSourceTracking, keyed by field path like "Storage[master].Port": the path of the file setting it, "env:NAME"
+	// for the environment variable overriding it or "default" for its default tag. Values left unchanged by every
+	// source are absent. It returns an empty map if source tracking is not enabled.
+	Sources() map[string]string
+	// ParseConfigFS works like ParseConfig but reads the configuration from the filesystem, e.g. an embed.FS.
+	ParseConfigFS(fsys fs.FS, structure interface{}, fileName string, directoryName ...string) error
+	// ParseConfigDir reads every configuration file of the directory, in sorted order, and deep-merges them into
+	// the structure, later files taking precedence. Files with an excluded extension and hidden files are skipped.
+	ParseConfigDir(structure interface{}, dir string) error
+	// ParseConfigProfile works like ParseConfig and deep-merges the profile specific file, e.g. "app.production",
+	// on top of the base file before unmarshalling, so the profile can set a value back to its zero value, e.g.
+	// "debug: false". If the profile is empty, the APP_ENV environment variable, or the one set with
+	// WithProfileFromEnv, is used.
+	// A missing profile specific file is not an error, the base file is used alone.
+	ParseConfigProfile(structure interface{}, fileName, profile string, directoryName ...string) error
+	// ParseConfigSection works like ParseConfig but only unmarshalls the top-level key named after the section,
+	// e.g. "production", for files holding every environment. If the section is empty, the APP_ENV environment
+	// variable, or the one set with WithProfileFromEnv, is used. The structure is replaced by the section, an absent
+	// section returns an error wrapping ErrSectionNotFound. The other sections are not unmarshalled, so they are not
+	// reported as unknown fields by WithStrictUnmarshal.
+	ParseConfigSection(structure interface{}, section, fileName string, directoryName ...string) error
+	// ListProfiles returns the sorted and deduplicated names of the configuration files found in the directories,
+	// e.g. ["app", "logging", "storage"], without their extension. Hidden files and files with an excluded extension
+	// are skipped, like ParseConfig does. If no directory is provided, it will use the default directory.
+	ListProfiles(directoryName ...string) ([]string, error)
+	// ParseConfigRecursive works like ParseConfig but also searches the subdirectories of the directory.
+	// It returns an error wrapping ErrAmbiguousConfig if more than one file matches.
+	ParseConfigRecursive(structure interface{}, fileName string, directoryName ...string) error
+	// RegisterParser registers an unmarshalling function for a file extension, e.g. "toml".
+	// It replaces any parser previously registered for the same extension.
+	RegisterParser(ext string, fn func(interface{}, []byte) error)
+	// RegisterAlias registers the parser of a file extension for another extension, e.g. "conf" parsed as "yaml".
+	// It returns an error wrapping ErrUnsupportedExt if no parser is registered for the extension.
+	RegisterAlias(alias, ext string) error
+	// RegisterDecodeHook registers a function converting the values of type from read from the configuration files,
+	// e.g. a string, into the fields of type to, e.g. a url.URL. Hooks converting strings into time.Duration and
+	// time.Time (RFC 3339) are registered by default. It replaces any hook previously registered for the same types.
+	RegisterDecodeHook(from, to reflect.Type, fn func(interface{}) (interface{}, error))
+	// ParseConfigFile reads the configuration file at the given path and unmarshalls it into a structure.
+	// Unlike ParseConfig, it does not scan a directory, the parser is selected by the file extension.
+	// The path "-" reads the standard input like ParseConfigStdin, parsed as YAML.
+	ParseConfigFile(structure interface{}, filePath string) error
+	// BindEnv populates the fields of a structure tagged with `env:"NAME"` from the environment variables.
+	// Fields without tag or whose variable is not set are left unchanged, unless a `default:"value"` tag is present.
+	// Supported field types are string, bool, integers, floats, time.Duration and pointers to them.
+	BindEnv(structure interface{}) error
+	// BindFlags populates the fields of a structure tagged with `flag:"name"` from the flags explicitly set on the
+	// command line, so they override the values read from the configuration file or the environment.
+	// Fields are converted like BindEnv does.
+	BindFlags(structure interface{}, flags *flag.FlagSet) error
+	// BindOverrides populates the fields of a structure tagged with `flag:"name"` from the overrides keyed by name,
+	// like BindFlags does.
+	BindOverrides(structure interface{}, overrides map[string]string) error
+	// ParseConfigBytes replaces the environment variables in the content and unmarshalls it into a structure.
+	// It uses the unmarshalling function provided to NewGoConfig or, if not provided, the YAML parser.
+	ParseConfigBytes(structure interface{}, content []byte) error
+	// ParseConfigReader reads the content from the reader and unmarshalls it like ParseConfigBytes.
+	ParseConfigReader(structure interface{}, r io.Reader) error
+	// ParseConfigURL fetches the configuration from the URL with a GET request, replaces the environment variables and
+	// unmarshalls it with the parser of its Content-Type, e.g. application/json, or of the extension of the URL path,
+	// falling back to YAML. The request is cancelled with the context or after the timeout set with WithHTTPTimeout,
+	// 30 seconds by default. A failed request or a non-2xx response returns an error wrapping ErrFetchingConfig.
+	ParseConfigURL(ctx context.Context, structure interface{}, url string) error
+	// ParseConfigStdin reads the content from the standard input, e.g. piped by another command, replaces the
+	// environment variables and unmarshalls it with the parser of the extension, e.g. "json", or as YAML if empty.
+	ParseConfigStdin(structure interface{}, ext string) error
+	// ParseConfigFromFile reads the content from the open file, e.g. a file descriptor passed by a sandbox, replaces the
+	// environment variables and unmarshalls it with the parser of the extension of its name, or as YAML if it has none.
+	// The file is read from its current offset and is not closed.
+	ParseConfigFromFile(structure interface{}, f *os.File) error
+	// RefreshAll loads the .env files like LoadEnv and then parses the configuration file like ParseConfig, so the
+	// references are replaced with the fresh variables, e.g. on SIGHUP. The structure is only updated when parsing
+	// succeeds and the cache, if enabled, is refreshed. The variables stay loaded if parsing fails.
+	RefreshAll(structure interface{}, envFiles []string, fileName string, directoryName ...string) error
+	// Watch parses the configuration again every time the configuration file or a file it includes changes,
+	// until the context is cancelled. It returns once the directories are being watched.
+	// Consecutive changes are debounced and onChange is called after every reload with a pointer to a new value of
+	// the structure type, or with nil and the parse error. The structure is only used for its type and is never
+	// modified: writing the new configuration into it from the watching goroutine, with an onChange only receiving
+	// the error, would race with the readers of the structure, which no lock taken by Watch can protect. The new
+	// value can be published safely by the caller instead, e.g. with an atomic.Pointer.
+	Watch(ctx context.Context, structure interface{}, fileName string, onChange func(interface{}, error),
+		directoryName ...string) error
+}
+
+// NewGoConfig creates a new GoConfig instance configured with the options.
+// An unmarshalling function can be provided as an option, like with WithUnmarshaller, it is used for every file
+// regardless of its extension, if not provided the parser is selected by the file extension (YAML, JSON and TOML
+// are registered by default).
+// It panics if an option is not supported.
+func NewGoConfig(opts ...Option) GoConfig {
+	g := &goConfig{
+		parsers:            newParserRegistry(defaultParsers()),
+		excludeExtensions:  defaultExcludeExtensions,
+		envCommentPrefixes: defaultEnvCommentPrefixes,
+		hooks:              newHookRegistry(),
+		defaultDir:         defaultConfigDir,
+		defaultEnvFile:     defaultEnvFile,
+		profileEnvVar:      defaultProfileEnvVar,
+		envSyntax:          defaultEnvSyntax,
+		envCascadeVar:      defaultProfileEnvVar,
+		envCascade:         defaultEnvCascade,
+		loadedEnv:          newLoadedEnv(),
+		httpTimeout:        defaultHTTPTimeout,
+	}
+	for _, opt := range opts {
+		if fn, ok := opt.(func(interface{}, []byte) error); ok {
+			opt = WithUnmarshaller(fn)
+		}
+
+		apply, ok := opt.(optionFunc)
+		if !ok {
+			panic(fmt.Sprintf("goconfig: unsupported option %T", opt))
+		}
+
+		apply(g)
+	}
+
+	return g
+}
+
+// NewGoConfigWithParser creates a new GoConfig instance configured with the options, using the parser for every file.
+// Unlike the unmarshalling function accepted by NewGoConfig, the parser receives the extension of the file, e.g.
+// "yaml" or "json", so a single function can handle several formats. Content parsed without file, like with
+// ParseConfigBytes, is given the "yaml" extension.
+func NewGoConfigWithParser(parser func(structure interface{}, content []byte, extension string) error,
+	opts ...Option) GoConfig {
+	return NewGoConfig(append(opts, optionFunc(func(g *goConfig) {
+		g.unmarshallFunc = parser
+	}))...)
+}
+
+// builtinParsers are the parsers of the built-in formats keyed by file extension, whatever the parsers registered.
+var builtinParsers = defaultParsers()
+
+// defaultParsers returns the parsers registered by default keyed by file extension.
+func defaultParsers() map[string]func(interface{}, []byte) error {
+	return map[string]func(interface{}, []byte) error{
+		"yaml":       unmarshallYAML,
+		"yml":        unmarshallYAML,
+		"json":       UnmarshallJSON,
+		"json5":      UnmarshallLenientJSON,
+		"toml":       UnmarshallTOML,
+		"properties": UnmarshallProperties,
+		"ini":        UnmarshallINI,
+	}
+}
+
+func (g goConfig) ParseConfig(structure interface{}, configName string, directoryName ...string) error {
+	configName = g.configName(configName)
+	if g.cache != nil {
+		return g.parseCached(structure, configName, directoryName)
+	}
+
+	return g.ParseConfigContext(context.Background(), structure, configName, directoryName...)
+}
+
+func (g goConfig) MustParseConfig(structure interface{}, configName string, directoryName ...string) {
+	if err := g.ParseConfig(structure, configName, directoryName...); err != nil {
+		panic(err)
+	}
+}
+
+func (g goConfig) ParseConfigContext(ctx context.Context, structure interface{}, configName string,
+	directoryName ...string) error {
+	target := reflect.ValueOf(structure)
+	if ctx.Done() == nil || target.Kind() != reflect.Pointer || target.IsNil() {
+		return g.ParseConfigFS(g.osFS(), structure, configName, directoryName...)
+	}
+
+	// The structure is parsed into a copy, keeping the fields filled in by the caller, so a cancelled or failed
+	// parse leaves it unchanged.
+	g.ctx = ctx
+	fresh := reflect.New(target.Type().Elem())
+	fresh.Elem().Set(deepCopy(target.Elem()))
+	if err := g.ParseConfigFS(g.osFS(), fresh.Interface(), configName, directoryName...); err != nil {
+		return err
+	}
+
+	target.Elem().Set(fresh.Elem())
+
+	return nil
+}
+
+func (g goConfig) ParseConfigFS(fsys fs.FS, structure interface{}, configName string, directoryName ...string) error {
+	if _, err := g.parseConfigFS(fsys, structure, configName, directoryName); err != nil {
+		return err
+	}
+
+	return g.postProcess(structure)
+}
+
+func (g goConfig) ParseConfigAs(structure interface{}, format, configName string, directoryName ...string) error {
+	g.format = normalizeExtension(format)
+	if _, err := g.unmarshaller(g.format); err != nil {
+		return err
+	}
+
+	return g.ParseConfigFS(g.osFS(), structure, configName, directoryName...)
+}
+
+func (g goConfig) ParseConfigWithPaths(structure interface{}, configName string, directoryName ...string) ([]string, error) {
+	paths, err := g.parseConfigFS(g.osFS(), structure, configName, directoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, filePath := range paths {
+		if paths[i], err = filepath.Abs(g.osFS().resolve(filePath)); err != nil {
+			return nil, fmt.Errorf(formatError, ErrReadingFile, err)
+		}
+	}
+
+	return paths, g.postProcess(structure)
 }
 
-// NewGoConfig creates a new GoConfig instance.
-// It receives an optional unmarshalling function, if not provided it will default to unmarshallYAML.
-func NewGoConfig(unmarshallFunc ...func(interface{}, []byte) error) GoConfig {
-	var unmarshall func(interface{}, []byte) error
-	if len(unmarshallFunc) > 0 {
-		unmarshall = unmarshallFunc[0]
-	} else {
-		unmarshall = unmarshallYAML
+// parseConfigFS reads the configuration file from every directory of the filesystem and deep-merges the results
+// into the structure, without post-processing it. It returns the paths of the files read.
+func (g goConfig) parseConfigFS(fsys fs.FS, structure interface{}, configName string, directoryName []string) ([]string, error) {
+	files, err := g.readConfigFS(fsys, configName, directoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.mergeFiles(structure, files); err != nil {
+		return nil, err
 	}
 
-	return &goConfig{unmarshallFunc: unmarshall}
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.path
+	}
+
+	return paths, nil
 }
 
-func (g goConfig) LoadEnv(envFiles ...string) error {
-	dir := "."
-	if len(envFiles) == 0 {
-		envFiles = []string{".env"}
+// readConfigFS reads the configuration file from every directory of the filesystem, followed by the files it
+// includes, in the order they are merged.
+func (g goConfig) readConfigFS(fsys fs.FS, configName string, directoryName []string) ([]configFile, error) {
+	configName = g.configName(configName)
+	var files []configFile
+	for _, dir := range g.configDirs(directoryName) {
+		if err := g.contextErr(); err != nil {
+			return nil, err
+		}
+
+		file, err := g.readFS(fsys, configName, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		included, err := g.expandIncludes(fsys, file, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, included...)
 	}
 
-	for _, envFile := range envFiles {
-		filePath := path.Join(dir, envFile)
-		file, err := openFile(filePath)
+	return files, nil
+}
+
+// mergeFiles unmarshalls the files into the structure, merging them in order: the files parsed by the built-in
+// parsers are merged as maps and unmarshalled at once, see unmarshallMerged, otherwise the first file is
+// unmarshalled into the structure and the next ones deep-merged into it, their zero values being ignored.
+// With WithYAMLConcat, the files are concatenated and unmarshalled at once instead.
+func (g goConfig) mergeFiles(structure interface{}, files []configFile) error {
+	g.resetSources()
+	if g.concatYAML && len(files) > 1 {
+		paths := make([]string, len(files))
+		for i, file := range files {
+			paths[i] = file.path
+		}
+
+		err := g.trackSources(structure, strings.Join(paths, ", "), func() error {
+			return g.unmarshallConcat(structure, files)
+		})
+		if err != nil {
+			return newConcatUnmarshalError(files, err)
+		}
+
+		g.deleteInclude(structure)
+
+		return nil
+	}
+
+	if format, ok := g.mapFormat(files); ok && len(files) > 1 {
+		if values, ok := g.decodeMaps(files); ok {
+			if err := g.unmarshallMerged(structure, files, values, format); err != nil {
+				return err
+			}
+
+			g.deleteInclude(structure)
+
+			return nil
+		}
+	}
+
+	for i, file := range files {
+		err := g.trackSources(structure, file.path, func() error {
+			if i == 0 {
+				return g.unmarshall(structure, file.content, file.extension)
+			}
+
+			return g.mergeInto(structure, file.content, file.extension)
+		})
+		if err != nil {
+			return newUnmarshalError(file.path, file.extension, err)
+		}
+	}
+
+	g.deleteInclude(structure)
+
+	return nil
+}
+
+// unmarshallConcat concatenates the YAML files and unmarshalls the result into the structure, so the anchors
+// defined in a file can be referenced by the aliases of the next ones. Other formats cannot be concatenated.
+func (g goConfig) unmarshallConcat(structure interface{}, files []configFile) error {
+	var content []byte
+	for _, file := range files {
+		if extension := normalizeExtension(file.extension); extension != "yaml" && extension != "yml" {
+			return fmt.Errorf("%w: %v cannot be concatenated, only YAML files can", ErrUnsupportedExt, file.path)
+		}
+
+		content = append(content, file.content...)
+		content = append(content, '\n')
+	}
+
+	return g.unmarshall(structure, content, files[0].extension)
+}
+
+func (g goConfig) ParseConfigDir(structure interface{}, dir string) error {
+	fsys := g.osFS()
+	names, err := g.configFileNames(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	files := make([]configFile, len(names))
+	for i, name := range names {
+		filePath := path.Join(dir, name)
+		content, err := g.readFileFS(fsys, filePath)
 		if err != nil {
 			return err
 		}
 
-		scanner := bufio.NewScanner(file)
-		if err := parseEnvFile(scanner); err != nil {
+		files[i] = configFile{path: filePath, extension: strings.TrimPrefix(filepath.Ext(name), "."), content: content}
+	}
+
+	files, err = g.expandAllIncludes(fsys, files)
+	if err != nil {
+		return err
+	}
+
+	if err := g.mergeFiles(structure, files); err != nil {
+		return err
+	}
+
+	return g.postProcess(structure)
+}
+
+// configFileNames returns the sorted names of the configuration files of the directory: the files with an extension
+// not excluded, ignoring hidden files like .env. If the directory has no configuration file, it returns an error.
+func (g goConfig) configFileNames(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v: %w", ErrOpenDir, dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		extension := strings.TrimPrefix(filepath.Ext(entry.Name()), ".")
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || extension == "" || !g.isExtensionAllowed(extension) {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: in directory %v", ErrConfigNotFound, dir)
+	}
+
+	return names, nil
+}
+
+func (g goConfig) ParseConfigProfile(structure interface{}, configName, profile string, directoryName ...string) error {
+	files, err := g.readConfigFS(g.osFS(), configName, directoryName)
+	if err != nil {
+		return err
+	}
+
+	if profile == "" {
+		profile = os.Getenv(g.profileEnvVar)
+	}
+
+	if profile != "" {
+		profileFiles, err := g.readProfile(configName+"."+profile, directoryName)
+		if err != nil {
 			return err
 		}
 
-		_ = file.Close()
+		files = append(files, profileFiles...)
 	}
 
-	return nil
+	if err := g.mergeFiles(structure, files); err != nil {
+		return err
+	}
+
+	return g.postProcess(structure)
 }
 
-func (g goConfig) ParseConfig(structure interface{}, configName string, directoryName ...string) error {
-	content, err := read(configName, directoryName...)
+func (g goConfig) ParseConfigSection(structure interface{}, section, configName string, directoryName ...string) error {
+	if err := checkStructure(structure); err != nil {
+		return err
+	}
+
+	if section == "" {
+		section = os.Getenv(g.profileEnvVar)
+	}
+
+	files, err := g.readConfigFS(g.osFS(), configName, directoryName)
 	if err != nil {
 		return err
 	}
 
-	if err := g.unmarshallFunc(structure, content); err != nil {
+	target := reflect.ValueOf(structure)
+	if _, ok := g.mapFormat(files); !ok {
+		return g.parseSectionField(target, section, configName, files)
+	}
+
+	var sections []configFile
+	for _, file := range files {
+		sectionFile, found, err := g.sectionFile(file, section)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			sections = append(sections, sectionFile)
+		}
+	}
+
+	if section == "" || len(sections) == 0 {
+		return fmt.Errorf("%w: %q in %v", ErrSectionNotFound, section, configName)
+	}
+
+	parsed := reflect.New(target.Type().Elem())
+	if err := g.mergeFiles(parsed.Interface(), sections); err != nil {
 		return err
 	}
 
+	target.Elem().Set(parsed.Elem())
+
+	return g.postProcess(structure)
+}
+
+// sectionFile returns the file holding only the value of the top-level key of the file named after the section,
+// encoded again in the format the file is merged in, so the other sections are not unmarshalled, e.g. by the strict
+// parsers. It returns false if the file has no such key.
+func (g goConfig) sectionFile(file configFile, section string) (configFile, bool, error) {
+	format, _ := g.parsers.format(file.extension)
+	values, ok := decodeMap(file.content, format)
+	if !ok {
+		err := g.unmarshall(&map[string]interface{}{}, file.content, file.extension)
+		if err == nil {
+			err = fmt.Errorf("%w: the top-level keys must be strings", ErrUnmarshalling)
+		}
+
+		return configFile{}, false, newUnmarshalError(file.path, file.extension, err)
+	}
+
+	value, found := values[section]
+	if node, isNode := value.(*yaml.Node); !found || value == nil || isNode && node.ShortTag() == "!!null" {
+		return configFile{}, false, nil
+	}
+
+	sectionValues, isMap := value.(map[string]interface{})
+	if !isMap {
+		return configFile{}, false, newUnmarshalError(file.path, file.extension,
+			fmt.Errorf("%w: the section %q is not a mapping", ErrUnmarshalling, section))
+	}
+
+	content, err := encodeMap(sectionValues, mapFormats[format])
+	if err != nil {
+		return configFile{}, false, fmt.Errorf(formatError, ErrUnmarshalling, err)
+	}
+
+	return configFile{path: file.path, extension: mapFormats[format], content: content}, true, nil
+}
+
+// parseSectionField unmarshalls the files into a struct whose only field is tagged with the section and replaces the
+// structure by the field, for the files that cannot be decoded into maps, e.g. with a custom unmarshaller.
+func (g goConfig) parseSectionField(target reflect.Value, section, configName string, files []configFile) error {
+	tag := fmt.Sprintf(`yaml:%q json:%q toml:%q`, section, section, section)
+	wrapper := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Section", Type: target.Type(), Tag: reflect.StructTag(tag)},
+	}))
+	if err := g.mergeFiles(wrapper.Interface(), files); err != nil {
+		return err
+	}
+
+	parsed := wrapper.Elem().Field(0)
+	if section == "" || parsed.IsNil() {
+		return fmt.Errorf("%w: %q in %v", ErrSectionNotFound, section, configName)
+	}
+
+	target.Elem().Set(parsed.Elem())
+
+	return g.postProcess(target.Interface())
+}
+
+// readProfile reads the profile specific file of every directory, followed by the files it includes, in the order
+// they are merged. Directories without the profile specific file are skipped.
+func (g goConfig) readProfile(profileName string, directoryName []string) ([]configFile, error) {
+	var files []configFile
+	for _, dir := range g.configDirs(directoryName) {
+		file, err := g.read(profileName, dir)
+		if errors.Is(err, ErrConfigNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		included, err := g.expandIncludes(g.osFS(), file, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, included...)
+	}
+
+	return files, nil
+}
+
+func (g goConfig) ParseConfigRecursive(structure interface{}, configName string, directoryName ...string) error {
+	file, err := g.readRecursive(g.configName(configName), directoryName...)
+	if err != nil {
+		return err
+	}
+
+	files, err := g.expandIncludes(g.osFS(), file, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := g.mergeFiles(structure, files); err != nil {
+		return err
+	}
+
+	return g.postProcess(structure)
+}
+
+func (g goConfig) ParseConfigBytes(structure interface{}, content []byte) error {
+	content, err := g.substituteEnv(normalizeContent(content))
+	if err != nil {
+		return err
+	}
+
+	return g.decode(structure, content, defaultExtension)
+}
+
+func (g goConfig) ParseConfigReader(structure interface{}, r io.Reader) error {
+	return g.parseReader(structure, r, defaultExtension)
+}
+
+func (g goConfig) ParseConfigStdin(structure interface{}, ext string) error {
+	if ext == "" {
+		ext = defaultExtension
+	}
+
+	return g.parseReader(structure, os.Stdin, ext)
+}
+
+func (g goConfig) ParseConfigFromFile(structure interface{}, f *os.File) error {
+	if f == nil {
+		return fmt.Errorf("%w: nil file", ErrReadingFile)
+	}
+
+	extension := filepath.Ext(f.Name())
+	if extension == "" {
+		extension = defaultExtension
+	}
+
+	return g.parseReader(structure, f, extension)
+}
+
+// parseReader reads the content from the reader, replaces the environment variables and unmarshalls it into the
+// structure with the parser of the extension.
+func (g goConfig) parseReader(structure interface{}, r io.Reader, extension string) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf(formatError, ErrReadingFile, err)
+	}
+
+	content, err = g.substituteEnv(normalizeContent(content))
+	if err != nil {
+		return err
+	}
+
+	return g.decode(structure, content, extension)
+}
+
+func (g goConfig) ParseConfigFile(structure interface{}, filePath string) error {
+	if filePath == stdinPath {
+		return g.ParseConfigStdin(structure, "")
+	}
+
+	content, err := g.readFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	extension := filepath.Ext(filePath)
+
+	return newUnmarshalError(filePath, extension, g.decode(structure, content, extension))
+}
+
+func (g goConfig) RegisterParser(ext string, fn func(interface{}, []byte) error) {
+	g.parsers.register(ext, fn)
+}
+
+func (g goConfig) RegisterAlias(alias, ext string) error {
+	if !g.parsers.alias(alias, ext) {
+		return fmt.Errorf(formatError, ErrUnsupportedExt, ext)
+	}
+
 	return nil
 }
 
-// read reads a file from a directory and returns its content and extension.
+// decode unmarshalls the content into the structure and post-processes it.
+func (g goConfig) decode(structure interface{}, content []byte, extension string) error {
+	if err := g.unmarshall(structure, content, extension); err != nil {
+		return err
+	}
+
+	return g.postProcess(structure)
+}
+
+// postProcess applies the enabled post-processing steps to the unmarshalled structure.
+// Encoded values are decoded first, then defaults are applied before validation, so a field with a default value
+// is never reported as missing.
+func (g goConfig) postProcess(structure interface{}) error {
+	if g.encodedValues {
+		if err := decodeValues(structure); err != nil {
+			return err
+		}
+	}
+
+	if g.envOverrides {
+		if err := applyEnvOverrides(structure, g.envOverridePrefix, g.setSource); err != nil {
+			return err
+		}
+	}
+
+	if g.defaults {
+		err := g.trackSources(structure, sourceDefault, func() error {
+			return applyDefaults(structure)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if g.validate {
+		return validateStructure(structure)
+	}
+
+	return nil
+}
+
+// unmarshall unmarshalls the content into the structure using the unmarshaller for the extension.
+func (g goConfig) unmarshall(structure interface{}, content []byte, extension string) error {
+	if err := checkStructure(structure); err != nil {
+		return err
+	}
+
+	unmarshall, err := g.unmarshaller(extension)
+	if err != nil {
+		return err
+	}
+
+	return g.hooks.unmarshall(structure, content, unmarshall)
+}
+
+// unmarshaller returns the unmarshalling function for the extension.
+// The function provided to NewGoConfig takes precedence over the registered parsers.
+func (g goConfig) unmarshaller(extension string) (func(interface{}, []byte) error, error) {
+	if g.unmarshallFunc != nil {
+		return func(structure interface{}, content []byte) error {
+			return g.unmarshallFunc(structure, content, normalizeExtension(extension))
+		}, nil
+	}
+
+	parser, ok := g.parsers.get(extension)
+	if !ok {
+		return nil, fmt.Errorf(formatError, ErrUnsupportedExt, extension)
+	}
+
+	return parser, nil
+}
+
+// isExtensionAllowed checks if the extension is not excluded and, when allowed extensions are configured, allowed.
+func (g goConfig) isExtensionAllowed(extension string) bool {
+	extension = normalizeExtension(extension)
+	if slices.Contains(g.excludeExtensions, extension) {
+		return false
+	}
+
+	return len(g.allowedExtensions) == 0 || slices.Contains(g.allowedExtensions, extension)
+}
+
+// normalizeExtension lowercases the extension and removes its leading dot.
+func normalizeExtension(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(ext, "."))
+}
+
+func (g goConfig) ListProfiles(directoryName ...string) ([]string, error) {
+	profiles := []string{}
+	for _, dir := range g.configDirs(directoryName) {
+		entries, err := fs.ReadDir(g.osFS(), dir)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v: %w", ErrOpenDir, dir, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+
+			if base, _, ok := g.splitConfigFile(entry.Name()); ok && !slices.Contains(profiles, base) {
+				profiles = append(profiles, base)
+			}
+		}
+	}
+
+	slices.Sort(profiles)
+
+	return profiles, nil
+}
+
+// read reads a file from a directory.
 // If no file is found, it returns an error.
-func read(fileName string, basePath ...string) ([]byte, error) {
-	dir := "config"
-	if len(basePath) > 0 {
-		dir = basePath[0]
+func (g goConfig) read(fileName string, basePath ...string) (configFile, error) {
+	return g.readFS(g.osFS(), fileName, g.configDir(basePath))
+}
+
+// readFS reads a file from a directory of the filesystem.
+// If no file or more than one file is found, it returns an error.
+func (g goConfig) readFS(fsys fs.FS, fileName, dir string) (configFile, error) {
+	if matches := g.statConfigFiles(fsys, fileName, dir); len(matches) > 0 {
+		return g.readMatch(fsys, fileName, matches)
 	}
 
-	files, err := os.ReadDir(dir)
+	files, err := fs.ReadDir(fsys, dir)
 	if err != nil {
-		return nil, fmt.Errorf(formatError, ErrOpenDir, basePath)
+		return configFile{}, fmt.Errorf("%w: %v: %w", ErrOpenDir, dir, err)
 	}
 
+	var names, matches []string
 	for _, file := range files {
-		name, extension, found := strings.Cut(file.Name(), ".")
-		if !found {
-			continue
+		names = append(names, file.Name())
+		if _, ok := g.matchConfigFile(file.Name(), fileName); ok && !file.IsDir() {
+			matches = append(matches, path.Join(dir, file.Name()))
 		}
+	}
+
+	g.log(EventConfigScan, map[string]interface{}{"dir": dir, "files": names})
+
+	if len(matches) == 0 && g.hasExtensionlessFile(names, fileName) {
+		return configFile{}, fmt.Errorf("%w: %v: the file %v has no extension, use ParseConfigAs to set its format",
+			ErrConfigNotFound, fileName, path.Join(dir, fileName))
+	}
+
+	return g.readMatch(fsys, fileName, matches)
+}
+
+// statConfigFiles looks up the configuration file with each allowed extension, or each extension with a registered
+// parser, directly, without scanning the directory, which is slow when it has thousands of entries. Only the exact
+// names can be looked up: when none is found, the directory is scanned, finding the names in another case, e.g.
+// "App.YAML", unless the names are matched case-sensitively. When a file is found, the other files named like the
+// configuration, e.g. "app.md", are not considered.
+func (g goConfig) statConfigFiles(fsys fs.FS, fileName, dir string) []string {
+	if g.format != "" {
+		return nil
+	}
+
+	extensions := g.allowedExtensions
+	if len(extensions) == 0 {
+		extensions = g.parsers.extensions()
+	}
 
-		if slices.Contains(excludeExtensions, extension) {
+	var names, matches []string
+	for _, extension := range extensions {
+		name := fileName + "." + extension
+		if !g.isExtensionAllowed(extension) {
 			continue
 		}
 
-		if strings.EqualFold(name, fileName) {
-			content, err := os.ReadFile(path.Join(dir, file.Name()))
-			if err != nil {
-				return nil, fmt.Errorf(formatError, ErrReadingFile, fileName)
-			}
+		if info, err := fs.Stat(fsys, path.Join(dir, name)); err == nil && !info.IsDir() {
+			names = append(names, name)
+			matches = append(matches, path.Join(dir, name))
+		}
+	}
+
+	if len(matches) > 0 {
+		g.log(EventConfigScan, map[string]interface{}{"dir": dir, "files": names})
+	}
+
+	return matches
+}
 
-			contentStr := replaceEnvVariables(string(content))
+// hasExtensionlessFile checks if a file without extension is named like the configuration, when the format is not
+// explicit, to report it instead of silently skipping it.
+func (g goConfig) hasExtensionlessFile(names []string, fileName string) bool {
+	return g.format == "" && slices.ContainsFunc(names, func(name string) bool {
+		return filepath.Ext(name) == "" && g.equalName(name, fileName)
+	})
+}
 
-			return []byte(contentStr), nil
+// readRecursive reads a file from a directory or any of its subdirectories.
+// If no file or more than one file is found, it returns an error.
+func (g goConfig) readRecursive(fileName string, basePath ...string) (configFile, error) {
+	dir := g.configDir(basePath)
+	var files, matches []string
+	err := filepath.WalkDir(g.osFS().resolve(dir), func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
+
+		files = append(files, filePath)
+		if _, ok := g.matchConfigFile(entry.Name(), fileName); ok && !entry.IsDir() {
+			matches = append(matches, filePath)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return configFile{}, fmt.Errorf("%w: %v: %w", ErrOpenDir, dir, err)
 	}
 
-	return nil, fmt.Errorf("%w: in profile %v", ErrUnsupportedExt, fileName)
+	g.log(EventConfigScan, map[string]interface{}{"dir": dir, "files": files})
+
+	return g.readMatch(g.osFS(), fileName, matches)
 }
 
-// replaceEnvVariables replaces the environment variables in the content using the format ${ENV_VAR}.
-// If the environment variable is not found, it will panic returning the name of the variable.
-func replaceEnvVariables(content string) string {
-	return regexEnv.ReplaceAllStringFunc(content, func(match string) string {
-		envVar := regexEnv.FindStringSubmatch(match)[1]
-		env := os.Getenv(envVar)
-		if env == "" {
-			panic(fmt.Errorf(formatError, ErrVariableNotFound, envVar))
+// readMatch reads the only file matching the configuration file name.
+// If no file or more than one file matches, it returns an error listing the conflicting files.
+func (g goConfig) readMatch(fsys fs.FS, fileName string, matches []string) (configFile, error) {
+	switch len(matches) {
+	case 0:
+		return configFile{}, fmt.Errorf("%w: %v", ErrConfigNotFound, fileName)
+	case 1:
+		extension, _ := g.matchConfigFile(path.Base(filepath.ToSlash(matches[0])), fileName)
+		g.log(EventConfigMatch, map[string]interface{}{"path": matches[0], "extension": extension})
+
+		content, err := g.readFileFS(fsys, matches[0])
+		if err != nil {
+			return configFile{}, err
 		}
 
-		return env
-	})
+		return configFile{path: matches[0], extension: extension, content: content}, nil
+	default:
+		return configFile{}, fmt.Errorf("%w: %v", ErrAmbiguousConfig, strings.Join(matches, ", "))
+	}
 }
 
-// unmarshallYAML unmarshalls the content into the structure.
-// Supported formats are YAML and JSON (JSON is a subset of YAML).
-func unmarshallYAML(structure interface{}, content []byte) error {
-	err := yaml.Unmarshal(content, structure)
+// configName returns the configuration name provided or, if it is empty and the profile is read from the
+// environment, the profile of the environment variable, falling back to the default profile when it is not set.
+func (g goConfig) configName(configName string) string {
+	if configName != "" || !g.profileFromEnv {
+		return configName
+	}
+
+	if profile := os.Getenv(g.profileEnvVar); profile != "" {
+		return profile
+	}
+
+	return g.defaultProfile
+}
+
+// configDir returns the directory provided or the default directory.
+func (g goConfig) configDir(basePath []string) string {
+	if len(basePath) > 0 {
+		return basePath[0]
+	}
+
+	return g.defaultDir
+}
+
+// configDirs returns the directories provided or the default directory.
+func (g goConfig) configDirs(basePath []string) []string {
+	if len(basePath) > 0 {
+		return basePath
+	}
+
+	return []string{g.defaultDir}
+}
+
+// matchConfigFile checks if the file name matches the requested configuration name and returns its extension.
+// The extension is the part after the last dot, so "app.production.yaml" matches "app.production".
+// Files without extension, with an excluded extension or, when allowed extensions are configured,
+// with an extension not allowed never match.
+func (g goConfig) matchConfigFile(name, fileName string) (string, bool) {
+	if g.format != "" {
+		return g.format, g.matchFormatFile(name, fileName)
+	}
+
+	base, extension, ok := g.splitConfigFile(name)
+	if !ok {
+		return "", false
+	}
+
+	return extension, g.equalName(base, fileName)
+}
+
+// matchFormatFile checks if the file name matches the requested configuration name when the format is explicit:
+// the whole name, e.g. a mounted "config" file without extension, or the name without any extension not excluded.
+func (g goConfig) matchFormatFile(name, fileName string) bool {
+	if g.equalName(name, fileName) {
+		return true
+	}
+
+	extension := strings.TrimPrefix(filepath.Ext(name), ".")
+	if extension == "" || slices.Contains(g.excludeExtensions, normalizeExtension(extension)) {
+		return false
+	}
+
+	return g.equalName(strings.TrimSuffix(name, "."+extension), fileName)
+}
+
+// equalName compares the names, ignoring case unless case-sensitive matching is enabled.
+func (g goConfig) equalName(name, fileName string) bool {
+	if g.caseSensitive {
+		return name == fileName
+	}
+
+	return strings.EqualFold(name, fileName)
+}
+
+// splitConfigFile splits the file name into its configuration name and extension, e.g. "app.production" and "yaml".
+// Files without extension, with an excluded extension or, when allowed extensions are configured,
+// with an extension not allowed are not configuration files.
+func (g goConfig) splitConfigFile(name string) (string, string, bool) {
+	extension := strings.TrimPrefix(filepath.Ext(name), ".")
+	if extension == "" || !g.isExtensionAllowed(extension) {
+		return "", "", false
+	}
+
+	return strings.TrimSuffix(name, "."+extension), extension, true
+}
+
+// readFile reads the file at the given path and replaces the environment variables in its content.
+func (g goConfig) readFile(filePath string) ([]byte, error) {
+	return g.readFileFS(g.osFS(), filePath)
+}
+
+// readFileFS reads the file at the given path of the filesystem and replaces the environment variables in its content.
+func (g goConfig) readFileFS(fsys fs.FS, filePath string) ([]byte, error) {
+	if err := g.contextErr(); err != nil {
+		return nil, err
+	}
+
+	if err := g.checkConfigPermissions(fsys, filePath); err != nil {
+		return nil, err
+	}
+
+	content, err := fs.ReadFile(fsys, filePath)
 	if err != nil {
-		return fmt.Errorf(formatError, ErrUnmarshalling, err)
+		return nil, fmt.Errorf("%w: %v: %w", ErrReadingFile, filePath, err)
+	}
+
+	return g.substituteEnv(normalizeContent(content))
+}
+
+// contextErr returns an error wrapping ErrReadingFile and the error of the context of ParseConfigContext when it
+// is done, so the directories and files left are not read.
+func (g goConfig) contextErr() error {
+	if g.ctx == nil {
+		return nil
+	}
+
+	if err := g.ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrReadingFile, err)
 	}
 
 	return nil
 }
 
-// openFile abstracts the logic of opening a file and returning a file handle.
-func openFile(filePath string) (*os.File, error) {
-	file, err := os.Open(filePath)
+// normalizeContent removes the UTF-8 byte order mark starting the content and replaces the CRLF line endings by LF,
+// so files saved on Windows parse like any other file.
+func normalizeContent(content []byte) []byte {
+	return bytes.ReplaceAll(bytes.TrimPrefix(content, utf8BOM), []byte("\r\n"), []byte("\n"))
+}
+
+// substituteEnv executes the content as a template, if enabled, and replaces the environment variables in the
+// result, unless substitution is disabled.
+func (g goConfig) substituteEnv(content []byte) ([]byte, error) {
+	if g.template {
+		var err error
+		if content, err = g.executeTemplate(content); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.noSubstitution {
+		return content, nil
+	}
+
+	contentStr, err := g.envSyntax.replace(string(content), g.logLookup(g.lookupValue))
 	if err != nil {
-		return nil, fmt.Errorf("%w: in %v", ErrOpeningEnvFile, filePath)
+		return nil, err
 	}
 
-	return file, nil
+	return []byte(contentStr), nil
 }
 
-// parseEnvFile reads and parses the .env file, setting the environment variables.
-func parseEnvFile(scanner *bufio.Scanner) error {
-	for scanner.Scan() {
-		line := scanner.Text()
-		if isCommentOrEmpty(line) {
-			continue
-		}
+// lookupValue resolves a reference with the value resolver, if any, falling back to the environment.
+func (g goConfig) lookupValue(key string) (string, bool) {
+	return g.resolveWith(os.LookupEnv)(key)
+}
 
-		if err := setEnvVarFromLine(regexEnvFromFile, line); err != nil {
-			return err
+// resolveWith returns a lookup resolving the references with the value resolver, if any, falling back to lookup.
+func (g goConfig) resolveWith(lookup func(key string) (string, bool)) func(key string) (string, bool) {
+	if g.resolver == nil {
+		return lookup
+	}
+
+	return func(key string) (string, bool) {
+		if value, ok := g.resolver(key); ok {
+			return value, true
 		}
+
+		return lookup(key)
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading .env file: %w", err)
+// unmarshallYAML unmarshalls the content into the structure.
+// JSON, a subset of YAML, is also accepted, e.g. by ParseConfigBytes.
+func unmarshallYAML(structure interface{}, content []byte) error {
+	err := yaml.Unmarshal(content, structure)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalling, err)
 	}
 
 	return nil
 }
 
-// isCommentOrEmpty checks if a line is a comment or empty.
-func isCommentOrEmpty(line string) bool {
-	return strings.HasPrefix(line, "#") || strings.TrimSpace(line) == ""
+// unmarshallYAMLStrict unmarshalls the content into the structure like unmarshallYAML,
+// but returns an error naming the keys that do not match any field of the structure.
+func unmarshallYAMLStrict(structure interface{}, content []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(structure); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %w", ErrUnmarshalling, err)
+	}
+
+	return nil
 }
 
-// setEnvVarFromLine parses a line and sets the corresponding environment variable.
-func setEnvVarFromLine(re *regexp.Regexp, line string) error {
-	parts := strings.SplitN(line, "=", 2)
-	if len(parts) != 2 {
-		return fmt.Errorf(formatError, ErrInvalidEnvFormat, line)
+// unmarshallTOMLStrict unmarshalls the TOML content into the structure like UnmarshallTOML,
+// but returns an error naming the keys that do not match any field of the structure.
+// Maps accept any key, the tables decoded into their interface{} values are not reported.
+func unmarshallTOMLStrict(structure interface{}, content []byte) error {
+	metadata, err := toml.NewDecoder(bytes.NewReader(content)).Decode(structure)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalling, err)
 	}
 
-	if !re.MatchString(line) {
-		return fmt.Errorf(formatError, ErrInvalidEnvFormat, line)
+	if reflect.Indirect(reflect.ValueOf(structure)).Kind() == reflect.Map {
+		return nil
 	}
 
-	key, value := parts[0], parts[1]
+	if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
 
-	return os.Setenv(key, value)
+		return fmt.Errorf("%w: unknown keys %v", ErrUnmarshalling, strings.Join(keys, ", "))
+	}
+
+	return nil
+}
+
+// UnmarshallTOML unmarshalls the TOML content into the structure.
+// It can be provided to NewGoConfig to read TOML configuration files.
+func UnmarshallTOML(structure interface{}, content []byte) error {
+	err := toml.Unmarshal(content, structure)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalling, err)
+	}
+
+	return nil
 }
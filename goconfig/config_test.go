@@ -1,12 +1,22 @@
 package goconfig_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"testing/iotest"
+	"time"
 
 	"github.com/jsalonl/go-config/v2/goconfig"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -28,83 +38,6 @@ func TestParseConfigWithCustomUnmarshall(t *testing.T) {
 	assert.NotNil(t, config)
 }
 
-func TestLoadEnvSuccess(t *testing.T) {
-	content := `APP_NAME=TestApp
-APP_VERSION=1.0
-`
-	createEnvFile(t, content)
-	config := goconfig.NewGoConfig()
-	assert.NotNil(t, config)
-
-	err := config.LoadEnv()
-	assert.NoError(t, err)
-
-	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
-	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
-
-	_ = os.Unsetenv("APP_NAME")
-	_ = os.Unsetenv("APP_VERSION")
-	removeEnvFile(t)
-}
-
-func TestLoadEnvSuccessWithComments(t *testing.T) {
-	content := `APP_NAME=TestApp
-# This is a comment
-APP_VERSION=1.0
-`
-	createEnvFile(t, content)
-	config := goconfig.NewGoConfig()
-	assert.NotNil(t, config)
-
-	err := config.LoadEnv()
-	assert.NoError(t, err)
-
-	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
-	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
-
-	_ = os.Unsetenv("APP_NAME")
-	_ = os.Unsetenv("APP_VERSION")
-	removeEnvFile(t)
-}
-
-func TestLoadEnvFailOpenDir(t *testing.T) {
-	config := goconfig.NewGoConfig()
-	assert.NotNil(t, config)
-
-	err := config.LoadEnv("nonexistent")
-	assert.Error(t, err)
-}
-
-func TestLoadEnvFailMatchString(t *testing.T) {
-	content := `APP_NAME:=TestApp
-APP_VERSION=1.0
-`
-	createEnvFile(t, content)
-	config := goconfig.NewGoConfig()
-	assert.NotNil(t, config)
-
-	err := config.LoadEnv()
-	assert.Error(t, err)
-	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
-
-	removeEnvFile(t)
-}
-
-func TestLoadEnvFailSplitN(t *testing.T) {
-	content := `APP_NAME:TestApp
-APP_VERSION:1.0
-`
-	createEnvFile(t, content)
-	config := goconfig.NewGoConfig()
-	assert.NotNil(t, config)
-
-	err := config.LoadEnv()
-	assert.Error(t, err)
-	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
-
-	removeEnvFile(t)
-}
-
 func TestParseConfigSuccessYAML(t *testing.T) {
 	content := `App:
   name: AppName
@@ -179,6 +112,25 @@ storage:
 	_ = os.Remove(filepath.Join(dir, file))
 }
 
+func TestParseConfigFailMultipleEnvNotFound(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	content := `App:
+  name: ${APP_NAME}
+  version: ${MISSING_VERSION}
+  log_level: ${MISSING_LEVEL:-${MISSING_FALLBACK}}
+storage:
+  master:
+    host: ${MISSING_HOST}
+    user: ${MISSING_VERSION}
+`
+	dir, _ := createConfigFile(t, content)
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.EqualError(t, err, "environment variable not found: MISSING_VERSION, MISSING_FALLBACK, MISSING_HOST")
+}
+
 func TestParseConfigFailEnvNotFound(t *testing.T) {
 	content := `App:
   name: ${APP_NAME}
@@ -206,134 +158,1219 @@ storage:
 	config := goconfig.NewGoConfig()
 	assert.NotNil(t, config)
 
-	assert.PanicsWithError(t, "environment variable not found: APP_NAME", func() {
-		_ = config.ParseConfig(&yamlCfg, "App", dir)
-	})
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.EqualError(t, err, "environment variable not found: APP_NAME")
 
 	_ = os.Remove(filepath.Join(dir, file))
 }
 
-func TestParseConfigFailUnsupportedFileExtension(t *testing.T) {
-	dir := t.TempDir()
-	unsupportedContent := `name: TestApp
-	version: 1.0`
-	err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(unsupportedContent), 0644)
-	assert.NoError(t, err)
+func TestParseConfigSuccessWithDefaultValue(t *testing.T) {
+	content := `App:
+  name: ${APP_NAME:-DefaultApp}
+  version: ${APP_VERSION:-http://localhost:8080/a-b}
+`
+	dir, file := createConfigFile(t, content)
 
 	var yamlCfg AppConfig
 	config := goconfig.NewGoConfig()
 	assert.NotNil(t, config)
 
-	err = config.ParseConfig(&yamlCfg, "app", dir)
-	assert.Error(t, err)
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "DefaultApp", yamlCfg.App.Name)
+	assert.Equal(t, "http://localhost:8080/a-b", yamlCfg.App.Version)
+
+	_ = os.Remove(filepath.Join(dir, file))
 }
 
-func TestParseConfigFailNoDirFound(t *testing.T) {
+func TestParseConfigSuccessEnvOverridesDefaultValue(t *testing.T) {
+	err := os.Setenv("APP_NAME", "TestApp")
+	assert.NoError(t, err)
+
+	content := `App:
+  name: ${APP_NAME:-DefaultApp}
+  version: ${APP_VERSION:-}
+`
+	dir, file := createConfigFile(t, content)
+
+	var yamlCfg AppConfig
 	config := goconfig.NewGoConfig()
 	assert.NotNil(t, config)
 
+	err = config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "", yamlCfg.App.Version)
+
+	_ = os.Unsetenv("APP_NAME")
+	_ = os.Remove(filepath.Join(dir, file))
+}
+
+func TestParseConfigSuccessEmptyEnvVariable(t *testing.T) {
+	t.Setenv("EMPTY_NAME", "")
+	t.Setenv("EMPTY_VERSION", "")
+	content := `App:
+  name: "${EMPTY_NAME}"
+  version: ${EMPTY_VERSION:-1.0}
+`
+	dir, _ := createConfigFile(t, content)
+
+	yamlCfg := AppConfig{App: App{Name: "Unchanged"}}
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "", yamlCfg.App.Name)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+
+	assert.NoError(t, os.Unsetenv("EMPTY_NAME"))
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+}
+
+func TestParseConfigSuccessNestedDefaultValue(t *testing.T) {
+	t.Setenv("FALLBACK_NAME", "FallbackApp")
+	t.Setenv("LAST_VERSION", "2.0")
+	content := `App:
+  name: ${PRIMARY_NAME:-${FALLBACK_NAME}}
+  version: ${PRIMARY_VERSION:-${FALLBACK_VERSION:-${LAST_VERSION}}}
+  log_level: ${PRIMARY_LEVEL:-${FALLBACK_LEVEL:-info}}-${FALLBACK_NAME}
+`
+	dir, _ := createConfigFile(t, content)
+
 	var yamlCfg AppConfig
-	err := config.ParseConfig(&yamlCfg, "app")
-	assert.Error(t, err)
-	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "FallbackApp", yamlCfg.App.Name)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+	assert.Equal(t, "info-FallbackApp", yamlCfg.App.LogLevel)
 }
 
-func TestParseConfigFailMultipleDirNotFound(t *testing.T) {
+func TestParseConfigFailNestedDefaultValue(t *testing.T) {
 	config := goconfig.NewGoConfig()
-	assert.NotNil(t, config)
 
 	var yamlCfg AppConfig
-	err := config.ParseConfig(&yamlCfg, "app", "configuration")
-	assert.Error(t, err)
-	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+	err := config.ParseConfigBytes(&yamlCfg, []byte("App:\n  name: ${PRIMARY_NAME:-${FALLBACK_NAME}}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.ErrorContains(t, err, "FALLBACK_NAME")
+
+	nested := "deep"
+	for i := 0; i < 11; i++ {
+		nested = "${UNDEFINED_VAR:-" + nested + "}"
+	}
+
+	err = config.ParseConfigBytes(&yamlCfg, []byte("App:\n  name: "+nested+"\n"))
+	assert.ErrorIs(t, err, goconfig.ErrEnvNestingTooDeep)
 }
 
-func TestParseConfigFailUnmarshall(t *testing.T) {
-	dir := t.TempDir()
-	content := `
-	name: TestApp
-version: 1.0
+func TestParseConfigSuccessTypedEnvVariable(t *testing.T) {
+	t.Setenv("PORT", "+5433")
+	t.Setenv("TIMEOUT", "90s")
+	content := `storage:
+  master:
+    port: ${PORT:int}
+    name: ${TIMEOUT:duration}
+    user: ${DEBUG:bool:-1}
+    host: $${PORT:int}
+  slave:
+    port: ${SLAVE_PORT:int:-5432}
 `
-	err := os.WriteFile(filepath.Join(dir, configFileYaml), []byte(content), 0644)
+	dir, _ := createConfigFile(t, content)
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "App", dir)
 	assert.NoError(t, err)
+	assert.Equal(t, 5433, yamlCfg.Storage["master"].Port)
+	assert.Equal(t, "1m30s", yamlCfg.Storage["master"].Name)
+	assert.Equal(t, "true", yamlCfg.Storage["master"].User)
+	assert.Equal(t, "${PORT:int}", yamlCfg.Storage["master"].Host)
+	assert.Equal(t, 5432, yamlCfg.Storage["slave"].Port)
+}
+
+func TestParseConfigFailTypedEnvVariable(t *testing.T) {
+	t.Setenv("PORT", "eighty")
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigBytes(&yamlCfg, []byte("storage:\n  master:\n    port: ${PORT:int}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, `PORT: "eighty" is not a valid int`)
+
+	err = config.ParseConfigBytes(&yamlCfg, []byte("storage:\n  master:\n    port: ${SLAVE_PORT:int:-none}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "SLAVE_PORT")
+
+	err = config.ParseConfigBytes(&yamlCfg, []byte("storage:\n  master:\n    port: ${SLAVE_PORT:int}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+}
+
+func TestParseConfigSuccessEscapedEnvVariable(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	content := `App:
+  name: ${APP_NAME}-$${APP_NAME}
+  version: $${UNDEFINED_VAR:-1.0}
+`
+	dir, _ := createConfigFile(t, content)
 
+	var yamlCfg AppConfig
 	config := goconfig.NewGoConfig()
 	assert.NotNil(t, config)
 
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp-${APP_NAME}", yamlCfg.App.Name)
+	assert.Equal(t, "${UNDEFINED_VAR:-1.0}", yamlCfg.App.Version)
+}
+
+func TestParseConfigSuccessDottedAndDashedEnvVariable(t *testing.T) {
+	t.Setenv("APP.NAME", "TestApp")
+	t.Setenv("my-service-url", "http://localhost:8080")
+	content := `App:
+  name: ${APP.NAME}
+  version: ${my-service-url}
+  log_level: ${app.log-level:-debug}
+`
+	dir, _ := createConfigFile(t, content)
+
 	var yamlCfg AppConfig
-	err = config.ParseConfig(&yamlCfg, "app", dir)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "http://localhost:8080", yamlCfg.App.Version)
+	assert.Equal(t, "debug", yamlCfg.App.LogLevel)
+}
+
+func TestParseConfigSuccessTOML(t *testing.T) {
+	dir := t.TempDir()
+	content := `[App]
+name = "AppName"
+version = "1.0"
+
+[storage.master]
+host = "master-pg.localhost"
+port = 5432
+`
+	err := os.WriteFile(filepath.Join(dir, "app.toml"), []byte(content), 0644)
+	assert.NoError(t, err)
+
+	var tomlCfg TOMLConfig
+	config := goconfig.NewGoConfig(goconfig.UnmarshallTOML)
+	assert.NotNil(t, config)
+
+	err = config.ParseConfig(&tomlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", tomlCfg.App.Name)
+	assert.Equal(t, "1.0", tomlCfg.App.Version)
+	assert.Equal(t, 5432, tomlCfg.Storage["master"].Port)
+}
+
+func TestParseConfigFailUnmarshallTOML(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.toml"), []byte("name = "), 0644)
+	assert.NoError(t, err)
+
+	var tomlCfg TOMLConfig
+	config := goconfig.NewGoConfig(goconfig.UnmarshallTOML)
+	assert.NotNil(t, config)
+
+	err = config.ParseConfig(&tomlCfg, "app", dir)
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
 }
 
-func TestParseConfigFailFileWithoutExtension(t *testing.T) {
+func TestParseConfigSuccessTOMLByExtension(t *testing.T) {
 	dir := t.TempDir()
-	err := os.WriteFile(filepath.Join(dir, "appconfig"), []byte("dummy content"), 0644)
+	content := `[App]
+name = "AppName"
+`
+	err := os.WriteFile(filepath.Join(dir, "app.toml"), []byte(content), 0644)
 	assert.NoError(t, err)
 
+	var tomlCfg TOMLConfig
 	config := goconfig.NewGoConfig()
 	assert.NotNil(t, config)
 
-	var yamlCfg AppConfig
-	err = config.ParseConfig(&yamlCfg, "app", dir)
-	assert.Error(t, err)
-	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+	err = config.ParseConfig(&tomlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", tomlCfg.App.Name)
 }
 
-func TestParseConfigFailReadingFile(t *testing.T) {
+func TestParseConfigSuccessRegisteredParser(t *testing.T) {
 	dir := t.TempDir()
-	err := os.WriteFile(filepath.Join(dir, configFileYaml), []byte("dummy content"), 0000)
+	err := os.WriteFile(filepath.Join(dir, "app.custom"), []byte("AppName"), 0644)
 	assert.NoError(t, err)
 
-	defer func(name string, mode os.FileMode) {
-		_ = os.Chmod(name, mode)
-	}(filepath.Join(dir, configFileYaml), 0644)
-
 	config := goconfig.NewGoConfig()
 	assert.NotNil(t, config)
+	config.RegisterParser(".custom", func(structure interface{}, content []byte) error {
+		structure.(*AppConfig).App.Name = string(content)
+		return nil
+	})
 
-	var yamlCfg AppConfig
-	err = config.ParseConfig(&yamlCfg, "app", dir)
-	assert.Error(t, err)
+	var cfg AppConfig
+	err = config.ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", cfg.App.Name)
 }
 
-func createEnvFile(t *testing.T, content string) {
-	dir := "."
-	err := os.WriteFile(filepath.Join(dir, ".env"), []byte(content), 0644)
+func TestParseConfigSuccessCustomUnmarshallOverridesParsers(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, configFileYaml), []byte("not: used"), 0644)
+	assert.NoError(t, err)
+
+	called := false
+	config := goconfig.NewGoConfig(func(structure interface{}, content []byte) error {
+		called = true
+		return nil
+	})
+	assert.NotNil(t, config)
+
+	var cfg AppConfig
+	err = config.ParseConfig(&cfg, "app", dir)
 	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestNewGoConfigWithParserSuccess(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.YAML":     "App:\n  name: YAMLName\n",
+		"storage.json": `{"App": {"name": "JSONName"}}`,
+	})
+
+	var extensions []string
+	config := goconfig.NewGoConfigWithParser(func(structure interface{}, content []byte, ext string) error {
+		extensions = append(extensions, ext)
+		switch ext {
+		case "yaml":
+			return yaml.Unmarshal(content, structure)
+		case "json":
+			return goconfig.UnmarshallJSON(structure, content)
+		default:
+			return goconfig.ErrUnsupportedExt
+		}
+	})
+
+	var yamlCfg, jsonCfg AppConfig
+	assert.NoError(t, config.ParseConfig(&yamlCfg, "app", dir))
+	assert.Equal(t, "YAMLName", yamlCfg.App.Name)
+	assert.NoError(t, config.ParseConfig(&jsonCfg, "storage", dir))
+	assert.Equal(t, "JSONName", jsonCfg.App.Name)
+	assert.Equal(t, []string{"yaml", "json"}, extensions)
 }
 
-func removeEnvFile(t *testing.T) {
-	dir := "."
-	err := os.Remove(filepath.Join(dir, ".env"))
+func TestParseConfigSuccessYML(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.yml"), []byte("App:\n  name: AppName\n"), 0644)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
 	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
 }
 
-func createConfigFile(t *testing.T, content string) (string, string) {
+func TestRegisterAliasSuccess(t *testing.T) {
 	dir := t.TempDir()
-	file := "App.yaml"
-	err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+	err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("App:\n  name: AppName\n"), 0644)
 	assert.NoError(t, err)
 
-	return dir, file
+	config := goconfig.NewGoConfig()
+	var yamlCfg AppConfig
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+
+	err = config.RegisterAlias(".CONF", "yaml")
+	assert.NoError(t, err)
+
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
 }
 
-type AppConfig struct {
-	App     App                `yaml:"App"`
-	Storage map[string]Storage `yaml:"storage"`
+func TestRegisterAliasFailUnknownExtension(t *testing.T) {
+	err := goconfig.NewGoConfig().RegisterAlias("conf", "hcl")
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
 }
 
-type App struct {
-	Name     string `yaml:"name"`
-	Version  string `yaml:"version"`
-	LogLevel string `yaml:"log_level"`
+func TestParseConfigFailNoParserForExtension(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.hcl"), []byte(`name = "TestApp"`), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var cfg AppConfig
+	err = config.ParseConfig(&cfg, "app", dir)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
 }
 
-type Storage struct {
-	Name     string `yaml:"name"`
-	Host     string `yaml:"host"`
-	Port     int    `yaml:"port"`
-	User     string `yaml:"user"`
-	Password string `yaml:"password"`
-	Database string `yaml:"database"`
+func TestParseConfigFileSuccess(t *testing.T) {
+	err := os.Setenv("APP_NAME", "TestApp")
+	assert.NoError(t, err)
+
+	content := `App:
+  name: ${APP_NAME}
+  version: 1.0
+`
+	dir, file := createConfigFile(t, content)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err = config.ParseConfigFile(&yamlCfg, filepath.Join(dir, file))
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+
+	_ = os.Unsetenv("APP_NAME")
+}
+
+func TestParseConfigFileFailFileNotFound(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigFile(&yamlCfg, filepath.Join(t.TempDir(), configFileYaml))
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrReadingFile)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseConfigRecursiveSuccess(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "service", "api")
+	err := os.MkdirAll(nested, 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(nested, configFileYaml), []byte("App:\n  name: AppName\n"), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+
+	err = config.ParseConfigRecursive(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestParseConfigRecursiveFailAmbiguous(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"service", "worker"} {
+		err := os.MkdirAll(filepath.Join(dir, sub), 0755)
+		assert.NoError(t, err)
+		err = os.WriteFile(filepath.Join(dir, sub, configFileYaml), []byte("App:\n  name: AppName\n"), 0644)
+		assert.NoError(t, err)
+	}
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigRecursive(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrAmbiguousConfig)
+	assert.Contains(t, err.Error(), filepath.Join("service", configFileYaml))
+	assert.Contains(t, err.Error(), filepath.Join("worker", configFileYaml))
+}
+
+func TestParseConfigRecursiveFailNoDirFound(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigRecursive(&yamlCfg, "app", "configuration")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestParseConfigBytesSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigBytes(&yamlCfg, []byte("App:\n  name: ${APP_NAME}\n  version: 1.0\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+}
+
+func TestParseConfigBytesFailEnvNotFound(t *testing.T) {
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigBytes(&yamlCfg, []byte("App:\n  name: ${APP_NAME}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+}
+
+func TestParseConfigReaderSuccessCustomUnmarshall(t *testing.T) {
+	var tomlCfg TOMLConfig
+	config := goconfig.NewGoConfig(goconfig.UnmarshallTOML)
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigReader(&tomlCfg, strings.NewReader("[App]\nname = \"AppName\"\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", tomlCfg.App.Name)
+}
+
+func TestParseConfigReaderFailReading(t *testing.T) {
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigReader(&yamlCfg, iotest.ErrReader(errors.New("connection reset")))
+	assert.ErrorIs(t, err, goconfig.ErrReadingFile)
+}
+
+func TestParseConfigFSSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	fsys := fstest.MapFS{
+		"config/app.go":        {Data: []byte("package config")},
+		"config/app.yaml":      {Data: []byte("App:\n  name: ${APP_NAME}\n  version: 1.0\n")},
+		"config/prod/app.yaml": {Data: []byte("App:\n  version: 2.0\n")},
+	}
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigFS(fsys, &yamlCfg, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+
+	err = config.ParseConfigFS(fsys, &yamlCfg, "app", "config", "config/prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+}
+
+func TestParseConfigFSFailNoDirFound(t *testing.T) {
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigFS(fstest.MapFS{}, &yamlCfg, "app")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestParseConfigWithPathsSuccess(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	prodDir := filepath.Join(dir, "prod")
+	assert.NoError(t, os.Mkdir(prodDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(prodDir, "App.json"), []byte(`{"App": {"version": "2.0"}}`), 0644))
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	paths, err := config.ParseConfigWithPaths(&yamlCfg, "App", dir, prodDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, file), filepath.Join(prodDir, "App.json")}, paths)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+}
+
+func TestParseConfigWithPathsSuccessRelativeDir(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+	relDir, err := filepath.Rel(cwd, dir)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	paths, err := config.ParseConfigWithPaths(&yamlCfg, "app", relDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, file)}, paths)
+}
+
+func TestParseConfigWithPathsFailNoDirFound(t *testing.T) {
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	paths, err := config.ParseConfigWithPaths(&yamlCfg, "app", "notfound")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+	assert.Nil(t, paths)
+}
+
+func TestParseConfigSuccessMultiDotFileNames(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.v1.yaml":   "App:\n  name: AppV1\n",
+		"app.prod.go":   "package config",
+		"app.yaml":      "App:\n  name: AppName\n",
+		"app.prod.json": `{"App": {"name": "AppProd"}}`,
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	for profile, expected := range map[string]string{"app": "AppName", "app.v1": "AppV1", "app.prod": "AppProd"} {
+		var yamlCfg AppConfig
+		err := config.ParseConfig(&yamlCfg, profile, dir)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, yamlCfg.App.Name)
+	}
+}
+
+func TestParseConfigFailUnsupportedFileExtension(t *testing.T) {
+	dir := t.TempDir()
+	unsupportedContent := `name: TestApp
+	version: 1.0`
+	err := os.WriteFile(filepath.Join(dir, "app.go"), []byte(unsupportedContent), 0644)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.Error(t, err)
+}
+
+func TestParseConfigFailAmbiguous(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	err := os.WriteFile(filepath.Join(dir, "App.json"), []byte(`{"App": {"name": "AppJSON"}}`), 0644)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err = config.ParseConfig(&yamlCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrAmbiguousConfig)
+	assert.ErrorContains(t, err, filepath.Join(dir, "App.json"))
+	assert.ErrorContains(t, err, filepath.Join(dir, "App.yaml"))
+}
+
+func TestParseConfigSuccessHomeDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configDir := filepath.Join(home, "myapp", "config")
+	assert.NoError(t, os.MkdirAll(configDir, 0755))
+	err := os.WriteFile(filepath.Join(configDir, "App.yaml"), []byte("App:\n  name: AppName\n"), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	paths, err := config.ParseConfigWithPaths(&yamlCfg, "App", "~/myapp/config")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, []string{filepath.Join(configDir, "App.yaml")}, paths)
+
+	var absCfg AppConfig
+	err = config.ParseConfig(&absCfg, "App", configDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", absCfg.App.Name)
+
+	var fileCfg AppConfig
+	err = config.ParseConfigFile(&fileCfg, "~/myapp/config/App.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", fileCfg.App.Name)
+}
+
+func TestParseConfigFailNoDirFound(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "app")
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestParseConfigFailNoDirFoundIncludesDirAndCause(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "app", "configuration")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Contains(t, err.Error(), "error opening directory: configuration:")
+}
+
+func TestParseConfigFailMultipleDirNotFound(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "app", "configuration")
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestParseConfigFailUnmarshall(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+	name: TestApp
+version: 1.0
+`
+	err := os.WriteFile(filepath.Join(dir, configFileYaml), []byte(content), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestParseConfigFailFileWithoutExtension(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "appconfig"), []byte("dummy content"), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestParseConfigAsSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	dir := createConfigFiles(t, map[string]string{
+		"config":      "App:\n  name: ${APP_NAME}\n",
+		"storage.cfg": `{"App": {"name": "JSONName"}}`,
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigAs(&yamlCfg, "yaml", "config", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+
+	var jsonCfg AppConfig
+	err = goconfig.NewGoConfig().ParseConfigAs(&jsonCfg, "JSON", "storage", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "JSONName", jsonCfg.App.Name)
+
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "config", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestParseConfigAsSuccessExtensionlessFile(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"appconfig": "App:\n  name: AppName\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "appconfig", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+	assert.ErrorContains(t, err, "has no extension, use ParseConfigAs")
+
+	err = goconfig.NewGoConfig().ParseConfigAs(&yamlCfg, "yaml", "AppConfig", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	err = goconfig.NewGoConfig(goconfig.WithCaseSensitiveMatch()).ParseConfigAs(&yamlCfg, "yaml", "AppConfig", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+	assert.NotContains(t, err.Error(), "has no extension")
+}
+
+func TestParseConfigAsFailUnsupportedFormat(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"config": "App:\n  name: AppName\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigAs(&yamlCfg, "hcl", "config", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+}
+
+func TestParseConfigFailReadingFile(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, configFileYaml), []byte("dummy content"), 0000)
+	assert.NoError(t, err)
+
+	defer func(name string, mode os.FileMode) {
+		_ = os.Chmod(name, mode)
+	}(filepath.Join(dir, configFileYaml), 0644)
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.Error(t, err)
+}
+
+func TestParseConfigContextSuccess(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigContext(ctx, &yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestParseConfigContextFailCancelled(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigContext(ctx, &yamlCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrReadingFile)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, yamlCfg.App.Name)
+}
+
+func TestParseConfigContextFailParsing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	yamlCfg := AppConfig{App: App{Name: "Unchanged"}}
+	err := goconfig.NewGoConfig().ParseConfigContext(ctx, &yamlCfg, "App", "notfound")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+	assert.Equal(t, "Unchanged", yamlCfg.App.Name)
+}
+
+func TestParseConfigContextSuccessKeepsFields(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	yamlCfg := AppConfig{App: App{Version: "1.0.0"}}
+	err := goconfig.NewGoConfig().ParseConfigContext(ctx, &yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, "1.0.0", yamlCfg.App.Version)
+}
+
+// cancelAfterContext is a context cancelled after its error has been checked a number of times.
+type cancelAfterContext struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.checks == 0 {
+		return context.Canceled
+	}
+
+	c.checks--
+
+	return nil
+}
+
+func TestParseConfigContextFailCancelledBetweenFiles(t *testing.T) {
+	base, _ := createConfigFile(t, "App:\n  name: Base\n")
+	override, _ := createConfigFile(t, "App:\n  name: Override\n")
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// The context is checked before scanning the first directory and before reading its file.
+	ctx := &cancelAfterContext{Context: parent, checks: 2}
+
+	yamlCfg := AppConfig{App: App{Version: "1.0.0"}}
+	err := goconfig.NewGoConfig().ParseConfigContext(ctx, &yamlCfg, "App", base, override)
+	assert.ErrorIs(t, err, goconfig.ErrReadingFile)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, yamlCfg.App.Name)
+	assert.Equal(t, "1.0.0", yamlCfg.App.Version)
+}
+
+// TestConcurrentLoadEnvAndParseConfig is meant to be run with the -race flag.
+func TestConcurrentLoadEnvAndParseConfig(t *testing.T) {
+	t.Setenv("CONCURRENT_APP_NAME", "")
+	envFile := "concurrent.env"
+	err := os.WriteFile(envFile, []byte("CONCURRENT_APP_NAME=TestApp\n"), 0644)
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove(envFile) }()
+
+	dir, _ := createConfigFile(t, "App:\n  name: ${CONCURRENT_APP_NAME:-DefaultApp}\n")
+	config := goconfig.NewGoConfig()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, config.LoadEnv(envFile))
+		}()
+		go func() {
+			defer wg.Done()
+
+			var yamlCfg AppConfig
+			assert.NoError(t, config.ParseConfig(&yamlCfg, "App", dir))
+			assert.Contains(t, []string{"TestApp", "DefaultApp"}, yamlCfg.App.Name)
+		}()
+		go func() {
+			defer wg.Done()
+			config.RegisterParser("yml", goconfig.UnmarshallTOML)
+		}()
+	}
+	wg.Wait()
+
+	var yamlCfg AppConfig
+	assert.NoError(t, config.ParseConfig(&yamlCfg, "App", dir))
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+}
+
+func createConfigFile(t *testing.T, content string) (string, string) {
+	dir := t.TempDir()
+	file := "App.yaml"
+	err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+	assert.NoError(t, err)
+
+	return dir, file
+}
+
+type AppConfig struct {
+	App     App                `yaml:"App"`
+	Storage map[string]Storage `yaml:"storage"`
+}
+
+type App struct {
+	Name     string `yaml:"name"`
+	Version  string `yaml:"version"`
+	LogLevel string `yaml:"log_level"`
+}
+
+type Storage struct {
+	Name     string `yaml:"name"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Database string `yaml:"database"`
+}
+
+type TOMLConfig struct {
+	App struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+	} `toml:"App"`
+	Storage map[string]struct {
+		Host string `toml:"host"`
+		Port int    `toml:"port"`
+	} `toml:"storage"`
+}
+
+func TestMustParseConfig(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig()
+
+	var appCfg AppConfig
+	assert.NotPanics(t, func() { config.MustParseConfig(&appCfg, "App", dir) })
+	assert.Equal(t, "AppName", appCfg.App.Name)
+
+	assert.Panics(t, func() { config.MustParseConfig(&appCfg, "Missing", dir) })
+}
+
+func TestParseConfigSuccessMap(t *testing.T) {
+	content := `App:
+  name: AppName
+  version: 1.0
+storage:
+  master:
+    host: master-pg.localhost
+    port: 5432
+`
+	dir, _ := createConfigFile(t, content)
+
+	var mapCfg map[string]any
+	err := goconfig.NewGoConfig(goconfig.WithDefaults(), goconfig.WithValidation()).ParseConfig(&mapCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "AppName", "version": 1.0}, mapCfg["App"])
+	assert.Equal(t, 5432, mapCfg["storage"].(map[string]any)["master"].(map[string]any)["port"])
+}
+
+func TestParseConfigFailInvalidStructure(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	var names []string
+	for _, structure := range []interface{}{yamlCfg, (*AppConfig)(nil), &names, nil} {
+		err := config.ParseConfig(structure, "App", dir)
+		assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+	}
+}
+
+func TestParseConfigSectionSuccess(t *testing.T) {
+	content := `development:
+  App:
+    name: DevApp
+    log_level: debug
+production:
+  App:
+    name: ProdApp
+  storage:
+    master:
+      host: master-pg.localhost
+`
+	dir, _ := createConfigFile(t, content)
+	config := goconfig.NewGoConfig()
+
+	var devCfg AppConfig
+	err := config.ParseConfigSection(&devCfg, "development", "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "DevApp", devCfg.App.Name)
+	assert.Equal(t, "debug", devCfg.App.LogLevel)
+
+	t.Setenv("APP_ENV", "production")
+	var prodCfg AppConfig
+	err = config.ParseConfigSection(&prodCfg, "", "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "ProdApp", prodCfg.App.Name)
+	assert.Equal(t, "master-pg.localhost", prodCfg.Storage["master"].Host)
+}
+
+func TestParseConfigSectionSuccessStrict(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml": "development:\n  App:\n    name: DevApp\nproduction:\n  App:\n    name: ProdApp\n",
+	})
+	prodDir := createConfigFiles(t, map[string]string{
+		"app.yaml": "development:\n  App:\n    log_level: debug\nproduction:\n  App:\n    log_level: warn\n",
+	})
+	tomlDir := createConfigFiles(t, map[string]string{
+		"app.toml": "[development.App]\nname = \"DevApp\"\n\n[production.App]\nname = \"ProdApp\"\n",
+	})
+	config := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal())
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigSection(&yamlCfg, "production", "app", dir, prodDir)
+	assert.NoError(t, err)
+	assert.Equal(t, App{Name: "ProdApp", LogLevel: "warn"}, yamlCfg.App)
+
+	var tomlCfg TOMLConfig
+	err = config.ParseConfigSection(&tomlCfg, "production", "app", tomlDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "ProdApp", tomlCfg.App.Name)
+
+	err = config.ParseConfigSection(&tomlCfg, "staging", "app", tomlDir)
+	assert.ErrorIs(t, err, goconfig.ErrSectionNotFound)
+}
+
+func TestParseConfigSectionFailNotFound(t *testing.T) {
+	dir, _ := createConfigFile(t, "development:\n  App:\n    name: DevApp\n")
+	config := goconfig.NewGoConfig()
+
+	yamlCfg := AppConfig{App: App{Name: "Unchanged"}}
+	err := config.ParseConfigSection(&yamlCfg, "staging", "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrSectionNotFound)
+	assert.Equal(t, "Unchanged", yamlCfg.App.Name)
+
+	t.Setenv("APP_ENV", "")
+	err = config.ParseConfigSection(&yamlCfg, "", "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrSectionNotFound)
+}
+
+func TestParseConfigFailConfigNotFound(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"other.yaml": "App:\n  name: AppName\n", "app.hcl": "name = 1"})
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "missing", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+	assert.NotErrorIs(t, err, goconfig.ErrUnsupportedExt)
+
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+	assert.NotErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestListProfilesSuccess(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":            "App:\n  name: AppName\n",
+		"app.production.yaml": "App:\n  name: ProdName\n",
+		"storage.json":        `{}`,
+		"storage.yaml":        "Storage: {}\n",
+		"logging.toml":        "",
+		"main.go":             "package main\n",
+		".env":                "APP_NAME=AppName\n",
+		"README":              "",
+	})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested.yaml"), 0755))
+
+	profiles, err := goconfig.NewGoConfig().ListProfiles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "app.production", "logging", "storage"}, profiles)
+
+	profiles, err = goconfig.NewGoConfig(goconfig.WithAllowedExtensions("json")).ListProfiles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"storage"}, profiles)
+}
+
+func TestListProfilesFailDirectoryNotFound(t *testing.T) {
+	_, err := goconfig.NewGoConfig().ListProfiles(filepath.Join(t.TempDir(), "notfound"))
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestParseConfigSuccessBOMAndCRLF(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":     "\xEF\xBB\xBFApp:\r\n  name: ${APP_NAME}\r\n  version: \"1.0.0\"\r\n",
+		"storage.json": "\xEF\xBB\xBF{\r\n  \"App\": {\"name\": \"JSONName\"}\r\n}\r\n",
+	})
+
+	var yamlCfg, jsonCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "1.0.0", yamlCfg.App.Version)
+
+	err = goconfig.NewGoConfig().ParseConfig(&jsonCfg, "storage", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "JSONName", jsonCfg.App.Name)
+}
+
+// setStdin replaces the standard input by a pipe fed with the content until the end of the test.
+func setStdin(t *testing.T, content string) {
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	go func() {
+		_, _ = w.WriteString(content)
+		_ = w.Close()
+	}()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	})
+}
+
+func TestParseConfigStdinSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	setStdin(t, "App:\n  name: ${APP_NAME}\n  version: 1.0.0\n")
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigFile(&yamlCfg, "-")
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "1.0.0", yamlCfg.App.Version)
+}
+
+func TestParseConfigStdinSuccessJSON(t *testing.T) {
+	setStdin(t, `{"app": {"name": "JSONApp"}}`)
+
+	var jsonCfg JSONConfig
+	err := goconfig.NewGoConfig().ParseConfigStdin(&jsonCfg, "json")
+	assert.NoError(t, err)
+	assert.Equal(t, "JSONApp", jsonCfg.App.Name)
+}
+
+func TestParseConfigStdinFailUnsupportedExt(t *testing.T) {
+	setStdin(t, "App:\n  name: TestApp\n")
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigStdin(&yamlCfg, "hcl")
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+}
+
+func TestParseConfigFailUnmarshalErrorPosition(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":    "App:\n  name: AppName\nstorage:\n  master:\n    port: not-a-number\n",
+		"app.json":    "{\n  \"app\": {\n    \"name\": 42\n  }\n}\n",
+		"syntax.yaml": "App:\n  name: AppName\n\tversion: 1.0.0\n",
+		"app.toml":    "[App]\nname = \"AppName\"\nversion = 1.0.0\n",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithAllowedExtensions("yaml")).ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+
+	var unmarshalErr *goconfig.UnmarshalError
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, filepath.Join(dir, "app.yaml"), unmarshalErr.File)
+		assert.Equal(t, "yaml", unmarshalErr.Format)
+		assert.Equal(t, 5, unmarshalErr.Line)
+		assert.True(t, strings.HasPrefix(err.Error(), filepath.Join(dir, "app.yaml")+":5: "), err.Error())
+	}
+
+	err = goconfig.NewGoConfig().ParseConfigFile(&yamlCfg, filepath.Join(dir, "syntax.yaml"))
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, filepath.Join(dir, "syntax.yaml"), unmarshalErr.File)
+		assert.Positive(t, unmarshalErr.Line)
+	}
+
+	var jsonCfg JSONConfig
+	err = goconfig.NewGoConfig().ParseConfigFile(&jsonCfg, filepath.Join(dir, "app.json"))
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, "json", unmarshalErr.Format)
+		assert.Equal(t, 3, unmarshalErr.Line)
+		assert.Equal(t, 14, unmarshalErr.Column)
+	}
+
+	var tomlCfg TOMLConfig
+	err = goconfig.NewGoConfig().ParseConfigFile(&tomlCfg, filepath.Join(dir, "app.toml"))
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, "toml", unmarshalErr.Format)
+		assert.Equal(t, 3, unmarshalErr.Line)
+		assert.Positive(t, unmarshalErr.Column)
+	}
+}
+
+func TestWithYAMLConcatFailUnmarshalErrorPosition(t *testing.T) {
+	baseDir := createConfigFiles(t, map[string]string{"app.yaml": "App:\n  name: AppName\n"})
+	dir := createConfigFiles(t, map[string]string{"app.yaml": "storage:\n  master:\n    port: not-a-number\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithYAMLConcat()).ParseConfig(&yamlCfg, "app", baseDir, dir)
+	var unmarshalErr *goconfig.UnmarshalError
+	if assert.ErrorAs(t, err, &unmarshalErr) {
+		assert.Equal(t, filepath.Join(dir, "app.yaml"), unmarshalErr.File)
+		assert.Equal(t, 3, unmarshalErr.Line)
+	}
+}
+
+func TestParseConfigFromFileSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	dir := createConfigFiles(t, map[string]string{"app.json": `{"app": {"name": "${APP_NAME}"}}`})
+
+	f, err := os.Open(filepath.Join(dir, "app.json"))
+	assert.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var jsonCfg JSONConfig
+	err = goconfig.NewGoConfig().ParseConfigFromFile(&jsonCfg, f)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", jsonCfg.App.Name)
+
+	err = goconfig.NewGoConfig().ParseConfigFromFile(&jsonCfg, nil)
+	assert.ErrorIs(t, err, goconfig.ErrReadingFile)
+}
+
+func BenchmarkParseConfigLargeDir(b *testing.B) {
+	files := map[string]string{"app.yaml": "App:\n  name: AppName\n"}
+	for i := 0; i < 5000; i++ {
+		files[fmt.Sprintf("file-%d.txt", i)] = ""
+	}
+	dir := createConfigFiles(b, files)
+
+	config := goconfig.NewGoConfig()
+	for name, configName := range map[string]string{"scan": "APP", "stat": "app"} {
+		b.Run(name, func(b *testing.B) {
+			var yamlCfg AppConfig
+			for i := 0; i < b.N; i++ {
+				if err := config.ParseConfig(&yamlCfg, configName, dir); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
 }
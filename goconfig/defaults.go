@@ -0,0 +1,66 @@
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// applyDefaults sets the fields still holding the zero value from their `default:"value"` tag.
+// Nil pointers to structs declaring defaults are allocated so their defaults are applied too.
+// Structures that are not pointers to a struct, like maps, are left unchanged.
+func applyDefaults(structure interface{}) error {
+	value, err := structValue(structure)
+	if err != nil {
+		return nil
+	}
+
+	return walkFields(value, "", applyDefaultField)
+}
+
+// applyDefaultField sets the field from its default tag if it holds the zero value.
+func applyDefaultField(field reflect.Value, structField reflect.StructField, path string) error {
+	if field.Kind() == reflect.Pointer && field.IsNil() && hasDefaults(field.Type().Elem()) {
+		field.Set(reflect.New(field.Type().Elem()))
+		return nil
+	}
+
+	raw, ok := structField.Tag.Lookup(tagDefault)
+	if !ok || !field.IsZero() {
+		return nil
+	}
+
+	if err := setValueFromString(field, raw); err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrConvertingValue, path, err)
+	}
+
+	return nil
+}
+
+// hasDefaults checks if the struct type, or any struct nested in it, declares a default tag.
+func hasDefaults(structType reflect.Type) bool {
+	if structType.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		if _, ok := structField.Tag.Lookup(tagDefault); ok {
+			return true
+		}
+
+		fieldType := structField.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if fieldType != structType && hasDefaults(fieldType) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package goconfig_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type DefaultsConfig struct {
+	App struct {
+		Name    string        `yaml:"name" default:"DefaultApp" validate:"required"`
+		Timeout time.Duration `yaml:"timeout" default:"5s"`
+	} `yaml:"App"`
+	Storage map[string]struct {
+		Host string `yaml:"host" default:"localhost"`
+		Port int    `yaml:"port" default:"5432"`
+	} `yaml:"storage"`
+	Cache *struct {
+		TTL int `yaml:"ttl" default:"60"`
+	} `yaml:"cache"`
+	Replicas *int `yaml:"replicas" default:"1"`
+}
+
+func TestWithDefaultsSuccess(t *testing.T) {
+	content := `App:
+  timeout: 10s
+storage:
+  master:
+    host: master-pg.localhost
+  slave:
+    port: 5433
+`
+	var cfg DefaultsConfig
+	config := goconfig.NewGoConfig(goconfig.WithDefaults(), goconfig.WithValidation())
+	err := config.ParseConfigBytes(&cfg, []byte(content))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "DefaultApp", cfg.App.Name)
+	assert.Equal(t, 10*time.Second, cfg.App.Timeout)
+	assert.Equal(t, "master-pg.localhost", cfg.Storage["master"].Host)
+	assert.Equal(t, 5432, cfg.Storage["master"].Port)
+	assert.Equal(t, "localhost", cfg.Storage["slave"].Host)
+	assert.Equal(t, 5433, cfg.Storage["slave"].Port)
+	assert.Equal(t, 60, cfg.Cache.TTL)
+	assert.Equal(t, 1, *cfg.Replicas)
+}
+
+func TestWithoutDefaultsFieldsUnchanged(t *testing.T) {
+	var cfg DefaultsConfig
+	config := goconfig.NewGoConfig()
+	err := config.ParseConfigBytes(&cfg, []byte("App:\n  timeout: 10s\n"))
+	assert.NoError(t, err)
+
+	assert.Empty(t, cfg.App.Name)
+	assert.Nil(t, cfg.Cache)
+}
+
+func TestWithDefaultsFailConvertingValue(t *testing.T) {
+	var cfg struct {
+		Port int `yaml:"port" default:"http"`
+	}
+	config := goconfig.NewGoConfig(goconfig.WithDefaults())
+	err := config.ParseConfigBytes(&cfg, []byte("name: AppName\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+}
@@ -0,0 +1,191 @@
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// absentValue is the value shown in a diff for a map entry or a slice element missing from a configuration.
+const absentValue = "<absent>"
+
+// Diff compares two configurations of the same type, e.g. two structs or maps parsed for different environments, and
+// returns the differences sorted by path, one per line formatted as "Storage[master].Port: 5432 -> 5433". Nested
+// structs, maps and slices are compared element by element, map entries and slice elements missing from one of the
+// configurations, like nil pointers, are shown as "<absent>", and the values of the fields tagged with
+// `secret:"true"` are masked. It returns an error wrapping ErrInvalidStructure if the configurations have different types.
+func Diff(a, b interface{}) ([]string, error) {
+	valueA, valueB := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !valueA.IsValid() || !valueB.IsValid() || valueA.Type() != valueB.Type() {
+		return nil, fmt.Errorf("%w: cannot compare %T and %T", ErrInvalidStructure, a, b)
+	}
+
+	d := &differ{}
+	d.diffValues(valueA, valueB, "", false)
+	slices.Sort(d.lines)
+
+	return d.lines, nil
+}
+
+// differ collects the differences between two configurations.
+type differ struct {
+	lines []string
+}
+
+// diffValues compares the values of the same type found at the path, secret being true if they must be masked.
+// One of the values is invalid when it is absent from its configuration, the leaves of the other one are then
+// compared to absent values.
+func (d *differ) diffValues(a, b reflect.Value, path string, secret bool) {
+	value := a
+	if !value.IsValid() {
+		value = b
+	}
+
+	valueType := value.Type()
+
+	switch valueType.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		d.diffElems(a, b, path, secret)
+	case reflect.Struct:
+		d.diffStructs(a, b, valueType, path, secret)
+	case reflect.Map:
+		d.diffMaps(a, b, path, secret)
+	case reflect.Slice, reflect.Array:
+		d.diffLists(a, b, path, secret)
+	default:
+		d.diffLeaves(a, b, path, secret)
+	}
+}
+
+// diffElems compares the values pointed by the pointers or held by the interfaces.
+func (d *differ) diffElems(a, b reflect.Value, path string, secret bool) {
+	elemA, elemB := elem(a), elem(b)
+	if !elemA.IsValid() && !elemB.IsValid() || elemA.IsValid() && elemB.IsValid() && elemA.Type() != elemB.Type() {
+		d.diffLeaves(a, b, path, secret)
+		return
+	}
+
+	d.diffValues(elemA, elemB, path, secret)
+}
+
+// diffStructs compares the exported fields of the structs, or the structs as a whole if they have none, e.g. time.Time.
+func (d *differ) diffStructs(a, b reflect.Value, structType reflect.Type, path string, secret bool) {
+	compared := false
+	for i := 0; i < structType.NumField(); i++ {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		compared = true
+		fieldSecret := secret || structField.Tag.Get(tagSecret) == "true"
+		d.diffValues(field(a, i), field(b, i), joinPath(path, structField.Name), fieldSecret)
+	}
+
+	if !compared {
+		d.diffLeaves(a, b, path, secret)
+	}
+}
+
+// diffMaps compares the entries of the maps with the same key.
+func (d *differ) diffMaps(a, b reflect.Value, path string, secret bool) {
+	keys := mapKeys(a)
+	for _, key := range mapKeys(b) {
+		if !mapIndex(a, key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		d.diffValues(mapIndex(a, key), mapIndex(b, key), fmt.Sprintf("%s[%v]", path, key), secret)
+	}
+}
+
+// diffLists compares the elements of the slices or arrays with the same index.
+func (d *differ) diffLists(a, b reflect.Value, path string, secret bool) {
+	for i := 0; i < max(listLen(a), listLen(b)); i++ {
+		d.diffValues(listIndex(a, i), listIndex(b, i), fmt.Sprintf("%s[%d]", path, i), secret)
+	}
+}
+
+// diffLeaves records the difference between the values if they are not equal.
+// A zero value compared to an absent value is not a difference.
+func (d *differ) diffLeaves(a, b reflect.Value, path string, secret bool) {
+	switch {
+	case a.IsValid() && b.IsValid() && reflect.DeepEqual(a.Interface(), b.Interface()):
+		return
+	case !a.IsValid() && b.IsZero(), !b.IsValid() && a.IsZero():
+		return
+	}
+
+	d.lines = append(d.lines, fmt.Sprintf("%v: %v -> %v", path, formatDiffValue(a, secret),
+		formatDiffValue(b, secret)))
+}
+
+// formatDiffValue formats the value shown in a diff, masking it if it is secret.
+func formatDiffValue(value reflect.Value, secret bool) string {
+	switch {
+	case !value.IsValid():
+		return absentValue
+	case secret:
+		return maskedValue
+	case (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && !value.IsNil():
+		return formatDiffValue(value.Elem(), secret)
+	default:
+		return fmt.Sprintf("%v", value.Interface())
+	}
+}
+
+// elem returns the value pointed by the pointer or held by the interface, or an invalid value if it is nil or absent.
+func elem(value reflect.Value) reflect.Value {
+	if !value.IsValid() || value.IsNil() {
+		return reflect.Value{}
+	}
+
+	return value.Elem()
+}
+
+// field returns the field of the struct at the index, or an invalid value if the struct is absent.
+func field(value reflect.Value, i int) reflect.Value {
+	if !value.IsValid() {
+		return reflect.Value{}
+	}
+
+	return value.Field(i)
+}
+
+// mapKeys returns the keys of the map, or none if the map is absent.
+func mapKeys(value reflect.Value) []reflect.Value {
+	if !value.IsValid() {
+		return nil
+	}
+
+	return value.MapKeys()
+}
+
+// mapIndex returns the entry of the map with the key, or an invalid value if the map or the entry is absent.
+func mapIndex(value reflect.Value, key reflect.Value) reflect.Value {
+	if !value.IsValid() {
+		return reflect.Value{}
+	}
+
+	return value.MapIndex(key)
+}
+
+// listLen returns the length of the slice or array, or zero if it is absent.
+func listLen(value reflect.Value) int {
+	if !value.IsValid() {
+		return 0
+	}
+
+	return value.Len()
+}
+
+// listIndex returns the element of the list at the index, or an invalid value if the list is absent or shorter.
+func listIndex(value reflect.Value, i int) reflect.Value {
+	if i >= listLen(value) {
+		return reflect.Value{}
+	}
+
+	return value.Index(i)
+}
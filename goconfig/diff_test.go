@@ -0,0 +1,67 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type DiffConfig struct {
+	App      App                `yaml:"App"`
+	Storage  map[string]Storage `yaml:"storage"`
+	Hosts    []string           `yaml:"hosts"`
+	Password string             `yaml:"password" secret:"true"`
+	Replicas *int               `yaml:"replicas"`
+}
+
+func TestDiffSuccess(t *testing.T) {
+	replicas := 3
+	staging := DiffConfig{
+		App:      App{Name: "App", Version: "1.0.0"},
+		Storage:  map[string]Storage{"master": {Host: "staging-pg", Port: 5432}, "cache": {Host: "redis"}},
+		Hosts:    []string{"a", "b"},
+		Password: "staging-secret",
+	}
+	production := DiffConfig{
+		App:      App{Name: "App", Version: "1.1.0"},
+		Storage:  map[string]Storage{"master": {Host: "prod-pg", Port: 5432}, "slave": {Host: "prod-pg-ro"}},
+		Hosts:    []string{"a", "c", "d"},
+		Password: "production-secret",
+		Replicas: &replicas,
+	}
+
+	diff, err := goconfig.Diff(&staging, &production)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"App.Version: 1.0.0 -> 1.1.0",
+		"Hosts[1]: b -> c",
+		"Hosts[2]: <absent> -> d",
+		"Password: ****** -> ******",
+		"Replicas: <absent> -> 3",
+		"Storage[cache].Host: redis -> <absent>",
+		"Storage[master].Host: staging-pg -> prod-pg",
+		"Storage[slave].Host: <absent> -> prod-pg-ro",
+	}, diff)
+
+	diff, err = goconfig.Diff(staging, staging)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffSuccessMaps(t *testing.T) {
+	a := map[string]interface{}{"app": map[string]interface{}{"name": "App", "port": 8080}, "debug": true}
+	b := map[string]interface{}{"app": map[string]interface{}{"name": "App", "port": "8081"}, "debug": true}
+
+	diff, err := goconfig.Diff(a, b)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"[app][port]: 8080 -> 8081"}, diff)
+}
+
+func TestDiffFailDifferentTypes(t *testing.T) {
+	_, err := goconfig.Diff(&DiffConfig{}, &AppConfig{})
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+
+	_, err = goconfig.Diff(nil, &AppConfig{})
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+}
@@ -0,0 +1,123 @@
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (g goConfig) ParseConfigDocument(structure interface{}, fileName string, index int,
+	directoryName ...string) error {
+	return g.parseDocument(structure, fileName, directoryName, func(content []byte) (*yaml.Node, error) {
+		return yamlDocument(content, index)
+	})
+}
+
+func (g goConfig) ParseConfigDocumentByName(structure interface{}, fileName, name string,
+	directoryName ...string) error {
+	return g.parseDocument(structure, fileName, directoryName, func(content []byte) (*yaml.Node, error) {
+		return yamlNamedDocument(content, name)
+	})
+}
+
+// parseDocument reads the YAML configuration file from the first directory, selects one of its documents and
+// unmarshalls it into the structure. The file is read without substituting the environment variables, which are
+// only substituted in the selected document, so the other documents may reference variables that are not set.
+func (g goConfig) parseDocument(structure interface{}, fileName string, directoryName []string,
+	selectDocument func(content []byte) (*yaml.Node, error)) error {
+	raw := g
+	raw.noSubstitution, raw.template = true, false
+	file, err := raw.read(g.configName(fileName), directoryName...)
+	if err != nil {
+		return err
+	}
+
+	switch normalizeExtension(file.extension) {
+	case "yaml", "yml":
+	default:
+		return fmt.Errorf("%w: %v cannot hold several YAML documents", ErrUnsupportedExt, file.path)
+	}
+
+	document, err := selectDocument(file.content)
+	if err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrDocumentNotFound, file.path, err)
+	}
+
+	content, err := yaml.Marshal(document)
+	if err != nil {
+		return fmt.Errorf(formatError, ErrUnmarshalling, err)
+	}
+
+	if file.content, err = g.substituteEnv(content); err != nil {
+		return err
+	}
+
+	files, err := g.expandIncludes(g.osFS(), file, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := g.mergeFiles(structure, files); err != nil {
+		return err
+	}
+
+	return g.postProcess(structure)
+}
+
+// yamlDocument returns the document of the YAML content at the index, the documents being separated by "---".
+func yamlDocument(content []byte, index int) (*yaml.Node, error) {
+	if index < 0 {
+		return nil, fmt.Errorf("invalid index %d", index)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for i := 0; ; i++ {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, fmt.Errorf("index %d out of range, the file has %d documents", index, i)
+		case err != nil:
+			return nil, fmt.Errorf(formatError, ErrUnmarshalling, err)
+		case i == index:
+			return &document, nil
+		}
+	}
+}
+
+// yamlNamedDocument returns the first document of the YAML content whose top-level "name" key is the name.
+func yamlNamedDocument(content []byte, name string) (*yaml.Node, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, fmt.Errorf("no document named %q", name)
+		case err != nil:
+			return nil, fmt.Errorf(formatError, ErrUnmarshalling, err)
+		case documentName(&document) == name:
+			return &document, nil
+		}
+	}
+}
+
+// documentName returns the value of the top-level "name" key of the YAML document, or an empty string if none.
+func documentName(document *yaml.Node) string {
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return ""
+	}
+
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if key.Value == "name" && value.Kind == yaml.ScalarNode {
+			return value.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,98 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+const multiDocumentContent = `App:
+  name: First
+---
+# second document
+App:
+  name: ${APP_NAME:-Second}
+  version: 2.0.0
+storage:
+  master:
+    port: 5432
+---
+App:
+  name: Third
+`
+
+func TestParseConfigDocumentSuccess(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": multiDocumentContent})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigDocument(&yamlCfg, "app", 1, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Second", yamlCfg.App.Name)
+	assert.Equal(t, "2.0.0", yamlCfg.App.Version)
+	assert.Equal(t, 5432, yamlCfg.Storage["master"].Port)
+}
+
+func TestParseConfigDocumentFailOutOfRange(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": multiDocumentContent})
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigDocument(&yamlCfg, "app", 3, dir)
+	assert.ErrorIs(t, err, goconfig.ErrDocumentNotFound)
+	assert.ErrorContains(t, err, "index 3 out of range, the file has 3 documents")
+
+	err = config.ParseConfigDocument(&yamlCfg, "app", -1, dir)
+	assert.ErrorIs(t, err, goconfig.ErrDocumentNotFound)
+}
+
+func TestParseConfigDocumentFailUnsupportedExt(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.toml": "[app]\nname = \"App\"\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigDocument(&yamlCfg, "app", 0, dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+}
+
+const namedDocumentContent = `name: production
+App:
+  name: ${PRODUCTION_APP_NAME}
+---
+name: staging
+App:
+  name: ${STAGING_APP_NAME:-Staging}
+  version: 2.0.0
+`
+
+func TestParseConfigDocumentByNameSuccess(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": namedDocumentContent})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigDocumentByName(&yamlCfg, "app", "staging", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Staging", yamlCfg.App.Name)
+	assert.Equal(t, "2.0.0", yamlCfg.App.Version)
+}
+
+func TestParseConfigDocumentByNameFailNotFound(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": namedDocumentContent})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigDocumentByName(&yamlCfg, "app", "development", dir)
+	assert.ErrorIs(t, err, goconfig.ErrDocumentNotFound)
+	assert.ErrorContains(t, err, `no document named "development"`)
+}
+
+func TestParseConfigDocumentSubstitutesSelectedDocumentOnly(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": namedDocumentContent})
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigDocument(&yamlCfg, "app", 1, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Staging", yamlCfg.App.Name)
+
+	err = config.ParseConfigDocument(&yamlCfg, "app", 0, dir)
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.ErrorContains(t, err, "PRODUCTION_APP_NAME")
+}
@@ -0,0 +1,57 @@
+package goconfig
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+const tagEncoding = "encoding"
+
+// decoders are the functions decoding the string fields by the value of their encoding tag.
+var decoders = map[string]func(string) ([]byte, error){
+	"base64": base64.StdEncoding.DecodeString,
+	"hex":    hex.DecodeString,
+}
+
+// decodeValues replaces the string fields tagged with `encoding:"base64"` or `encoding:"hex"` by their decoded value.
+// Structures that are not pointers to a struct, like maps, are left unchanged.
+func decodeValues(structure interface{}) error {
+	value, err := structValue(structure)
+	if err != nil {
+		return nil
+	}
+
+	return walkFields(value, "", decodeField)
+}
+
+// decodeField decodes the field with the decoder of its encoding tag.
+func decodeField(field reflect.Value, structField reflect.StructField, path string) error {
+	encoding, ok := structField.Tag.Lookup(tagEncoding)
+	if !ok {
+		return nil
+	}
+
+	decode, ok := decoders[encoding]
+	if !ok {
+		return fmt.Errorf("%w: %v: unsupported encoding %q", ErrConvertingValue, path, encoding)
+	}
+
+	for field.Kind() == reflect.Pointer && !field.IsNil() {
+		field = field.Elem()
+	}
+
+	if field.Kind() != reflect.String {
+		return fmt.Errorf("%w: %v: encoding %q requires a string field", ErrConvertingValue, path, encoding)
+	}
+
+	decoded, err := decode(field.String())
+	if err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrConvertingValue, path, err)
+	}
+
+	field.SetString(string(decoded))
+
+	return nil
+}
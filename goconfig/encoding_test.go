@@ -0,0 +1,50 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type EncodedConfig struct {
+	Password string  `yaml:"password" encoding:"base64"`
+	Key      *string `yaml:"key" encoding:"hex"`
+	Plain    string  `yaml:"plain"`
+}
+
+func TestWithEncodedValuesSuccess(t *testing.T) {
+	content := "password: c2VjcmV0\nkey: 6b6579\nplain: c2VjcmV0\n"
+	config := goconfig.NewGoConfig(goconfig.WithEncodedValues())
+
+	var cfg EncodedConfig
+	err := config.ParseConfigBytes(&cfg, []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", cfg.Password)
+	assert.Equal(t, "key", *cfg.Key)
+	assert.Equal(t, "c2VjcmV0", cfg.Plain)
+
+	var rawCfg EncodedConfig
+	err = goconfig.NewGoConfig().ParseConfigBytes(&rawCfg, []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "c2VjcmV0", rawCfg.Password)
+}
+
+func TestWithEncodedValuesFailInvalidEncoding(t *testing.T) {
+	config := goconfig.NewGoConfig(goconfig.WithEncodedValues())
+
+	var cfg EncodedConfig
+	err := config.ParseConfigBytes(&cfg, []byte("password: not base64!\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "Password")
+
+	err = config.ParseConfigBytes(&cfg, []byte("password: c2VjcmV0\nkey: xyz\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "Key")
+
+	var unsupported struct {
+		Port int `yaml:"port" encoding:"rot13"`
+	}
+	err = config.ParseConfigBytes(&unsupported, []byte("port: 80\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+}
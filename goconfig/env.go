@@ -0,0 +1,592 @@
+package goconfig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// BareKeyPolicy is the policy applied to the keys without value of the .env files, like "DEBUG".
+type BareKeyPolicy int
+
+const (
+	// BareKeyError rejects the keys without value with an error wrapping ErrInvalidEnvFormat, the default.
+	BareKeyError BareKeyPolicy = iota
+	// BareKeyEmpty sets the keys without value to an empty string.
+	BareKeyEmpty
+	// BareKeyTrue sets the keys without value to "true".
+	BareKeyTrue
+)
+
+// DuplicateKeyPolicy is the policy applied to the keys defined more than once in a .env file.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeyLast sets the keys defined more than once to their last value, the default.
+	DuplicateKeyLast DuplicateKeyPolicy = iota
+	// DuplicateKeyFirst keeps the first value of the keys defined more than once.
+	DuplicateKeyFirst
+	// DuplicateKeyError rejects the keys defined more than once with an error wrapping ErrDuplicateEnvKey.
+	DuplicateKeyError
+)
+
+// envProfilePlaceholder is replaced by the profile in the suffixes of the .env files cascade.
+const envProfilePlaceholder = "{env}"
+
+// envSourceKeyword starts the lines of the .env files loading another file inline, e.g. "source .env.shared".
+const envSourceKeyword = "source"
+
+var (
+	// defaultEnvCascade are the suffixes of the .env files loaded by LoadEnvCascade, in order.
+	defaultEnvCascade = []string{"", ".{env}", ".local", ".{env}.local"}
+	// envMu serializes the .env files loaded into the environment, so concurrent loads do not interleave.
+	envMu sync.Mutex
+
+	regexEnvFromFile  = regexp.MustCompile(`(?s)^\s*([\w.-]+)\s*=\s*(.*)?\s*$`)
+	regexEnvKey       = regexp.MustCompile(`^[\w.-]+$`)
+	envEscapeReplacer = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\"`, `"`)
+)
+
+// envParser parses .env files, storing every variable found with set.
+type envParser struct {
+	// set stores a parsed variable.
+	set func(key, value string) error
+	// lookup resolves the ${VAR} references found in the values.
+	lookup func(key string) (string, bool)
+	// syntax is the syntax of the ${VAR} references.
+	syntax envSyntax
+	// log logs the events of the parsing.
+	log func(event string, fields map[string]interface{})
+	// aggregate continues parsing after an invalid line and returns every error found.
+	aggregate bool
+	// commentPrefixes are the prefixes of the comment lines.
+	commentPrefixes []string
+	// defaultFile is the .env file loaded when no file is provided.
+	defaultFile string
+	// bareKeys is the policy of the keys without value.
+	bareKeys BareKeyPolicy
+	// permissions rejects the files readable by their group or by other users.
+	permissions bool
+	// duplicateKeys is the policy of the keys defined more than once in a file.
+	duplicateKeys DuplicateKeyPolicy
+	// expansion expands the ${VAR} references of the values.
+	expansion bool
+	// includes loads the files referenced by the source lines inline.
+	includes bool
+	// sourcing is the chain of files being loaded, to detect circular includes.
+	sourcing []string
+}
+
+// newEnvParser creates an envParser configured with the options of the instance.
+func (g goConfig) newEnvParser(set func(key, value string) error, lookup func(key string) (string, bool)) envParser {
+	return envParser{
+		set:             set,
+		lookup:          g.logLookup(g.resolveWith(lookup)),
+		syntax:          g.envSyntax,
+		log:             g.log,
+		aggregate:       g.envAggregateErrors,
+		commentPrefixes: g.envCommentPrefixes,
+		defaultFile:     g.defaultEnvFile,
+		bareKeys:        g.envBareKeys,
+		permissions:     g.strictPermissions,
+		duplicateKeys:   g.envDuplicateKeys,
+		expansion:       g.envExpansion && !g.noSubstitution,
+		includes:        g.envIncludes,
+	}
+}
+
+func (g goConfig) LoadEnv(envFiles ...string) error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	return g.newEnvParser(g.loadedEnv.setEnv, os.LookupEnv).loadEnv(envFiles...)
+}
+
+func (g goConfig) MustLoadEnv(envFiles ...string) {
+	if err := g.LoadEnv(envFiles...); err != nil {
+		panic(err)
+	}
+}
+
+func (g goConfig) LoadEnvCascade() error {
+	profile := os.Getenv(g.envCascadeVar)
+	var envFiles []string
+	for _, suffix := range g.envCascade {
+		if strings.Contains(suffix, envProfilePlaceholder) {
+			if profile == "" {
+				continue
+			}
+
+			suffix = strings.ReplaceAll(suffix, envProfilePlaceholder, profile)
+		}
+
+		envFile := g.defaultEnvFile + suffix
+		if _, err := os.Stat(envFile); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		envFiles = append(envFiles, envFile)
+	}
+
+	if len(envFiles) == 0 {
+		return nil
+	}
+
+	return g.LoadEnv(envFiles...)
+}
+
+func (g goConfig) LoadEnvIfAbsent(envFiles ...string) error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	return g.newEnvParser(g.loadedEnv.setEnvIfAbsent, os.LookupEnv).loadEnv(envFiles...)
+}
+
+func (g goConfig) ParseEnv(envFiles ...string) (map[string]string, error) {
+	env := make(map[string]string)
+	set := func(key, value string) error {
+		env[key] = value
+		return nil
+	}
+	lookup := func(key string) (string, bool) {
+		if value, ok := env[key]; ok {
+			return value, true
+		}
+
+		return os.LookupEnv(key)
+	}
+	parser := g.newEnvParser(set, lookup)
+
+	if err := parser.loadEnv(envFiles...); err != nil {
+		if g.envAggregateErrors {
+			return env, err
+		}
+
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// loadEnv parses the .env files.
+// If no files are provided, it will use the default file.
+func (p envParser) loadEnv(envFiles ...string) error {
+	if len(envFiles) == 0 {
+		envFiles = []string{p.defaultFile}
+	}
+
+	filePaths, err := expandEnvFiles(envFiles)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, filePath := range filePaths {
+		if err := p.loadEnvFile(filePath); err != nil {
+			if !p.aggregate {
+				return err
+			}
+
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// expandEnvFiles returns the paths of the .env files, the glob patterns like "*.env" being replaced by the files
+// they match in sorted order. A pattern matching no file returns an error.
+func expandEnvFiles(envFiles []string) ([]string, error) {
+	var filePaths []string
+	for _, envFile := range envFiles {
+		if !strings.ContainsAny(envFile, "*?[") {
+			filePaths = append(filePaths, filepath.Clean(envFile))
+			continue
+		}
+
+		matches, err := filepath.Glob(envFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v: %w", ErrOpeningEnvFile, envFile, err)
+		}
+
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%w: no file matches %v", ErrOpeningEnvFile, envFile)
+		}
+
+		slices.Sort(matches)
+		filePaths = append(filePaths, matches...)
+	}
+
+	return filePaths, nil
+}
+
+// loadEnvFile opens and parses a single .env file.
+func (p envParser) loadEnvFile(filePath string) error {
+	if slices.Contains(p.sourcing, filepath.Clean(filePath)) {
+		chain := append(slices.Clip(p.sourcing), filepath.Clean(filePath))
+		return fmt.Errorf(formatError, ErrCircularInclude, strings.Join(chain, " -> "))
+	}
+
+	p.sourcing = append(slices.Clip(p.sourcing), filepath.Clean(filePath))
+	file, err := openFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	if p.permissions {
+		if err := checkEnvFilePermissions(file, filePath); err != nil {
+			return err
+		}
+	}
+
+	p.log(EventEnvLoad, map[string]interface{}{"file": filePath})
+
+	return p.parseEnvFile(&lineScanner{Scanner: bufio.NewScanner(file)}, filePath)
+}
+
+// loadedEnv tracks the environment variables set by LoadEnv and LoadEnvIfAbsent with their prior values,
+// so UnloadEnv can restore them. It is guarded by envMu.
+type loadedEnv struct {
+	prior map[string]*string
+}
+
+// newLoadedEnv creates a tracker without loaded variables.
+func newLoadedEnv() *loadedEnv {
+	return &loadedEnv{prior: make(map[string]*string)}
+}
+
+// setEnv sets the environment variable, recording its prior value the first time it is set.
+func (l *loadedEnv) setEnv(key, value string) error {
+	if _, ok := l.prior[key]; !ok {
+		if prior, ok := os.LookupEnv(key); ok {
+			l.prior[key] = &prior
+		} else {
+			l.prior[key] = nil
+		}
+	}
+
+	return os.Setenv(key, value)
+}
+
+// setEnvIfAbsent sets the environment variable only if it is not already present.
+func (l *loadedEnv) setEnvIfAbsent(key, value string) error {
+	if _, ok := os.LookupEnv(key); ok {
+		return nil
+	}
+
+	return l.setEnv(key, value)
+}
+
+func (g goConfig) UnloadEnv() error {
+	envMu.Lock()
+	defer envMu.Unlock()
+
+	for key, prior := range g.loadedEnv.prior {
+		var err error
+		if prior == nil {
+			err = os.Unsetenv(key)
+		} else {
+			err = os.Setenv(key, *prior)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		delete(g.loadedEnv.prior, key)
+	}
+
+	return nil
+}
+
+// openFile abstracts the logic of opening a file and returning a file handle.
+func openFile(filePath string) (*os.File, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: in %v", ErrOpeningEnvFile, filePath)
+	}
+
+	return file, nil
+}
+
+// lineScanner is a bufio.Scanner counting the lines scanned.
+type lineScanner struct {
+	*bufio.Scanner
+	line int
+}
+
+// Scan advances the scanner to the next line, counting it.
+func (s *lineScanner) Scan() bool {
+	if !s.Scanner.Scan() {
+		return false
+	}
+
+	s.line++
+
+	return true
+}
+
+// Text returns the line scanned without its trailing carriage return, for files with CRLF line endings,
+// nor the UTF-8 byte order mark starting the first line.
+func (s *lineScanner) Text() string {
+	line := strings.TrimSuffix(s.Scanner.Text(), "\r")
+	if s.line == 1 {
+		line = strings.TrimPrefix(line, string(utf8BOM))
+	}
+
+	return line
+}
+
+// parseEnvFile reads and parses the .env file, setting the environment variables.
+// The errors are prefixed with the file path and the number of the line where the failing variable starts.
+// When aggregating errors, the invalid lines are skipped and every error is returned joined.
+func (p envParser) parseEnvFile(scanner *lineScanner, filePath string) error {
+	seen := make(map[string]int)
+	var errs []error
+	for scanner.Scan() {
+		line := scanner.Text()
+		if p.isCommentOrEmpty(line) {
+			continue
+		}
+
+		start := scanner.line
+		line, err := scanMultilineValue(scanner, line)
+		if source, ok := p.sourcedFile(line); ok && err == nil {
+			err = p.loadEnvFile(resolveSourcedFile(source, filePath))
+		} else if err == nil {
+			err = p.withDuplicateKeys(seen, start).setEnvVarFromLine(regexEnvFromFile, line)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v:%d: %w", filePath, start, err))
+		}
+
+		if err != nil && !p.aggregate {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("error reading .env file %v: %w", filePath, err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// withDuplicateKeys returns a copy of the parser applying the duplicate keys policy to the variable set from the line,
+// seen holding the line of the first definition of every key of the file.
+func (p envParser) withDuplicateKeys(seen map[string]int, line int) envParser {
+	set := p.set
+	p.set = func(key, value string) error {
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = line
+			return set(key, value)
+		}
+
+		switch p.duplicateKeys {
+		case DuplicateKeyFirst:
+			return nil
+		case DuplicateKeyError:
+			return fmt.Errorf("%w: %v is defined on lines %d and %d", ErrDuplicateEnvKey, key, first, line)
+		default:
+			return set(key, value)
+		}
+	}
+
+	return p
+}
+
+// sourcedFile returns the file referenced by a source line like "source .env.shared", when includes are enabled.
+func (p envParser) sourcedFile(line string) (string, bool) {
+	if !p.includes {
+		return "", false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != envSourceKeyword {
+		return "", false
+	}
+
+	return fields[1], true
+}
+
+// resolveSourcedFile returns the path of the sourced file, relative paths being resolved against the directory of
+// the file sourcing it.
+func resolveSourcedFile(source, filePath string) string {
+	if filepath.IsAbs(source) {
+		return source
+	}
+
+	return filepath.Join(filepath.Dir(filePath), source)
+}
+
+// scanMultilineValue appends the following lines of the scanner to the line while its quoted value is not closed.
+// The lines are joined with real newlines.
+func scanMultilineValue(scanner *lineScanner, line string) (string, error) {
+	for hasUnclosedQuote(line) {
+		if !scanner.Scan() {
+			return "", fmt.Errorf("%w: unterminated quoted value: %v", ErrInvalidEnvFormat, line)
+		}
+
+		line += "\n" + scanner.Text()
+	}
+
+	return line, nil
+}
+
+// hasUnclosedQuote checks if the value of a .env line starts with a quote that is not closed.
+func hasUnclosedQuote(line string) bool {
+	_, value, found := strings.Cut(line, "=")
+	value = strings.TrimSpace(value)
+	if !found || value == "" || (value[0] != '"' && value[0] != '\'') {
+		return false
+	}
+
+	return closingQuoteIndex(value) < 0
+}
+
+// isCommentOrEmpty checks if a line is empty or, ignoring its leading whitespace, starts with a comment prefix.
+func (p envParser) isCommentOrEmpty(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return true
+	}
+
+	for _, prefix := range p.commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setEnvVarFromLine parses a line and sets the corresponding environment variable.
+func (p envParser) setEnvVarFromLine(re *regexp.Regexp, line string) error {
+	line = trimExportPrefix(line)
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) != 2 {
+		return p.setBareKey(line)
+	}
+
+	if !re.MatchString(line) {
+		return fmt.Errorf(formatError, ErrInvalidEnvFormat, line)
+	}
+
+	value, err := p.parseEnvValue(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return err
+	}
+
+	key := strings.TrimSpace(parts[0])
+	p.log(EventEnvVariable, map[string]interface{}{"key": key, "value": maskValue(key, value)})
+
+	return p.set(key, value)
+}
+
+// setBareKey sets a key without value, like "DEBUG", according to the bare keys policy.
+func (p envParser) setBareKey(line string) error {
+	key := strings.TrimSpace(line)
+	if p.bareKeys == BareKeyError || !regexEnvKey.MatchString(key) {
+		return fmt.Errorf(formatError, ErrInvalidEnvFormat, line)
+	}
+
+	value := ""
+	if p.bareKeys == BareKeyTrue {
+		value = "true"
+	}
+
+	p.log(EventEnvVariable, map[string]interface{}{"key": key, "value": maskValue(key, value)})
+
+	return p.set(key, value)
+}
+
+// trimExportPrefix removes an optional leading "export" keyword from a .env line.
+func trimExportPrefix(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	rest, found := strings.CutPrefix(trimmed, "export")
+	if found && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+		return rest
+	}
+
+	return line
+}
+
+// parseEnvValue returns the value of a .env line without quotes and inline comments.
+// When expansion is enabled, the ${VAR} references of unquoted and double-quoted values are expanded,
+// single-quoted values are always literal.
+func (p envParser) parseEnvValue(value string) (string, error) {
+	unquoted, ok := unquoteEnvValue(value)
+	if ok && value[0] == '\'' {
+		return unquoted, nil
+	}
+
+	if !ok {
+		unquoted = stripInlineComment(value)
+	}
+
+	if !p.expansion {
+		return unquoted, nil
+	}
+
+	return p.syntax.replace(unquoted, p.lookup)
+}
+
+// unquoteEnvValue removes one matching pair of surrounding quotes from a .env value, ignoring a trailing comment.
+// Single-quoted values are literal, double-quoted values support the \n, \" and \\ escapes.
+// It returns false if the value is not quoted.
+func unquoteEnvValue(value string) (string, bool) {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		return "", false
+	}
+
+	end := closingQuoteIndex(value)
+	if end < 0 {
+		return "", false
+	}
+
+	if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", false
+	}
+
+	if value[0] == '\'' {
+		return value[1:end], true
+	}
+
+	return envEscapeReplacer.Replace(value[1:end]), true
+}
+
+// closingQuoteIndex returns the index of the quote closing the one at the start of the value, or -1 if not found.
+func closingQuoteIndex(value string) int {
+	quote := value[0]
+	for i := 1; i < len(value); i++ {
+		switch {
+		case quote == '"' && value[i] == '\\':
+			i++
+		case value[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// stripInlineComment removes a trailing comment, a "#" at the start of the value or preceded by whitespace.
+func stripInlineComment(value string) string {
+	for i := 0; i < len(value); i++ {
+		if value[i] == '#' && (i == 0 || value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimRight(value[:i], " \t")
+		}
+	}
+
+	return value
+}
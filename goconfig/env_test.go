@@ -0,0 +1,542 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadEnvSuccess(t *testing.T) {
+	content := `APP_NAME=TestApp
+APP_VERSION=1.0
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
+
+	_ = os.Unsetenv("APP_NAME")
+	_ = os.Unsetenv("APP_VERSION")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithComments(t *testing.T) {
+	content := `APP_NAME=TestApp
+# This is a comment
+APP_VERSION=1.0
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
+
+	_ = os.Unsetenv("APP_NAME")
+	_ = os.Unsetenv("APP_VERSION")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithQuotedValues(t *testing.T) {
+	content := `GREETING="hello world"
+PASSWORD='p@ss#word'
+MESSAGE="line1\nline2 \"quoted\" #not-a-comment"
+LITERAL='no\nescape'
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "hello world", os.Getenv("GREETING"))
+	assert.Equal(t, "p@ss#word", os.Getenv("PASSWORD"))
+	assert.Equal(t, "line1\nline2 \"quoted\" #not-a-comment", os.Getenv("MESSAGE"))
+	assert.Equal(t, `no\nescape`, os.Getenv("LITERAL"))
+
+	_ = os.Unsetenv("GREETING")
+	_ = os.Unsetenv("PASSWORD")
+	_ = os.Unsetenv("MESSAGE")
+	_ = os.Unsetenv("LITERAL")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithInlineComments(t *testing.T) {
+	content := `PORT=8080 # the http port
+HOST=localhost#not-a-comment
+PASSWORD="p@ss # word" # quoted hash is kept
+TOKEN='abc#def'	# single quoted
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "8080", os.Getenv("PORT"))
+	assert.Equal(t, "localhost#not-a-comment", os.Getenv("HOST"))
+	assert.Equal(t, "p@ss # word", os.Getenv("PASSWORD"))
+	assert.Equal(t, "abc#def", os.Getenv("TOKEN"))
+
+	_ = os.Unsetenv("PORT")
+	_ = os.Unsetenv("HOST")
+	_ = os.Unsetenv("PASSWORD")
+	_ = os.Unsetenv("TOKEN")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithSpacesAroundDelimiter(t *testing.T) {
+	content := `APP_NAME = Test App  
+  APP_VERSION=	1.0
+GREETING = "  hello  "
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Test App", os.Getenv("APP_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
+	assert.Equal(t, "  hello  ", os.Getenv("GREETING"))
+
+	_ = os.Unsetenv("APP_NAME")
+	_ = os.Unsetenv("APP_VERSION")
+	_ = os.Unsetenv("GREETING")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvOverridesExistingVariables(t *testing.T) {
+	t.Setenv("APP_NAME", "FromEnvironment")
+	createEnvFile(t, "APP_NAME=FromFile\n")
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "FromFile", os.Getenv("APP_NAME"))
+
+	removeEnvFile(t)
+}
+
+func TestLoadEnvIfAbsentKeepsExistingVariables(t *testing.T) {
+	t.Setenv("APP_NAME", "FromEnvironment")
+	createEnvFile(t, `APP_NAME=FromFile
+APP_VERSION=1.0
+`)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnvIfAbsent()
+	assert.NoError(t, err)
+	assert.Equal(t, "FromEnvironment", os.Getenv("APP_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
+
+	_ = os.Unsetenv("APP_VERSION")
+	removeEnvFile(t)
+}
+
+func TestParseEnvSuccess(t *testing.T) {
+	content := `APP_NAME=TestApp
+# This is a comment
+APP_VERSION=1.0
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	env, err := config.ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp", "APP_VERSION": "1.0"}, env)
+
+	_, found := os.LookupEnv("APP_NAME")
+	assert.False(t, found)
+
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithIndentedComments(t *testing.T) {
+	content := `    # indented comment
+APP_NAME=TestApp
+	# tab indented comment
+`
+	createEnvFile(t, content)
+	defer removeEnvFile(t)
+	t.Setenv("APP_NAME", "")
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
+}
+
+func TestParseEnvFailInvalidFormat(t *testing.T) {
+	createEnvFile(t, "APP_NAME:TestApp\n")
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	env, err := config.ParseEnv()
+	assert.Nil(t, env)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	removeEnvFile(t)
+}
+
+func TestParseEnvFailInvalidFormatIncludesFileAndLine(t *testing.T) {
+	content := `# Application
+APP_NAME=TestApp
+MULTILINE="first
+second"
+APP_NAME:=TestApp
+`
+	createEnvFile(t, content)
+	defer removeEnvFile(t)
+	err := os.WriteFile("other.env", []byte("APP_VERSION=1.0\nAPP_PORT=${UNDEFINED_PORT}\n"), 0644)
+	assert.NoError(t, err)
+	defer func() { _ = os.Remove("other.env") }()
+
+	config := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion())
+	assert.NotNil(t, config)
+
+	_, err = config.ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+	assert.EqualError(t, err, ".env:5: invalid .env format: APP_NAME:=TestApp")
+
+	_, err = config.ParseEnv("other.env")
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.EqualError(t, err, "other.env:2: environment variable not found: UNDEFINED_PORT")
+}
+
+func TestLoadEnvSuccessWithExportPrefix(t *testing.T) {
+	content := `export APP_NAME=TestApp
+APP_VERSION=1.0
+exported=true
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
+	assert.Equal(t, "true", os.Getenv("exported"))
+
+	_ = os.Unsetenv("APP_NAME")
+	_ = os.Unsetenv("APP_VERSION")
+	_ = os.Unsetenv("exported")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvFailExportPrefixInvalidFormat(t *testing.T) {
+	createEnvFile(t, "export APP_NAME\n")
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithMultilineValues(t *testing.T) {
+	content := `PRIVATE_KEY="-----BEGIN KEY-----
+abc#def
+-----END KEY-----"
+JSON='{
+  "name": "TestApp"
+}'
+APP_VERSION=1.0
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "-----BEGIN KEY-----\nabc#def\n-----END KEY-----", os.Getenv("PRIVATE_KEY"))
+	assert.Equal(t, "{\n  \"name\": \"TestApp\"\n}", os.Getenv("JSON"))
+	assert.Equal(t, "1.0", os.Getenv("APP_VERSION"))
+
+	_ = os.Unsetenv("PRIVATE_KEY")
+	_ = os.Unsetenv("JSON")
+	_ = os.Unsetenv("APP_VERSION")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvFailUnterminatedMultilineValue(t *testing.T) {
+	content := `PRIVATE_KEY="-----BEGIN KEY-----
+abc
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	removeEnvFile(t)
+}
+
+func TestLoadEnvFailOpenDir(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv("nonexistent")
+	assert.Error(t, err)
+}
+
+func TestLoadEnvFailOpenFileIncludesPath(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv("nonexistent.env")
+	assert.ErrorIs(t, err, goconfig.ErrOpeningEnvFile)
+	assert.EqualError(t, err, "error opening .env file: in nonexistent.env")
+}
+
+func TestLoadEnvFailMatchString(t *testing.T) {
+	content := `APP_NAME:=TestApp
+APP_VERSION=1.0
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	removeEnvFile(t)
+}
+
+func TestLoadEnvFailSplitN(t *testing.T) {
+	content := `APP_NAME:TestApp
+APP_VERSION:1.0
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	removeEnvFile(t)
+}
+
+func createEnvFile(t *testing.T, content string) {
+	dir := "."
+	err := os.WriteFile(filepath.Join(dir, ".env"), []byte(content), 0644)
+	assert.NoError(t, err)
+}
+
+func removeEnvFile(t *testing.T) {
+	dir := "."
+	err := os.Remove(filepath.Join(dir, ".env"))
+	assert.NoError(t, err)
+}
+
+func TestLoadEnvSuccessWithVariableExpansion(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	content := `BASE_URL=http://${HOST}
+API_URL="${BASE_URL}/api"
+RAW_URL='${BASE_URL}/raw'
+PORT=${APP_PORT:-8080}
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion())
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "http://localhost", os.Getenv("BASE_URL"))
+	assert.Equal(t, "http://localhost/api", os.Getenv("API_URL"))
+	assert.Equal(t, "${BASE_URL}/raw", os.Getenv("RAW_URL"))
+	assert.Equal(t, "8080", os.Getenv("PORT"))
+
+	_ = os.Unsetenv("BASE_URL")
+	_ = os.Unsetenv("API_URL")
+	_ = os.Unsetenv("RAW_URL")
+	_ = os.Unsetenv("PORT")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvFailVariableExpansionForwardReference(t *testing.T) {
+	content := `API_URL=${BASE_URL}/api
+BASE_URL=http://localhost
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion())
+	assert.NotNil(t, config)
+
+	err := config.LoadEnv()
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+
+	removeEnvFile(t)
+}
+
+func TestParseEnvSuccessWithVariableExpansion(t *testing.T) {
+	content := `BASE_URL=http://localhost
+API_URL=${BASE_URL}/api
+`
+	createEnvFile(t, content)
+	config := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion())
+	assert.NotNil(t, config)
+
+	env, err := config.ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost/api", env["API_URL"])
+
+	_, found := os.LookupEnv("BASE_URL")
+	assert.False(t, found)
+
+	removeEnvFile(t)
+}
+
+func TestMustLoadEnv(t *testing.T) {
+	createEnvFile(t, "APP_NAME=TestApp\n")
+	config := goconfig.NewGoConfig()
+
+	assert.NotPanics(t, func() { config.MustLoadEnv() })
+	assert.Equal(t, "TestApp", os.Getenv("APP_NAME"))
+	assert.Panics(t, func() { config.MustLoadEnv("missing.env") })
+
+	_ = os.Unsetenv("APP_NAME")
+	removeEnvFile(t)
+}
+
+func TestLoadEnvSuccessWithGlobPattern(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.env"), []byte("GLOB_NAME=Base\nGLOB_VERSION=1.0\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.env"), []byte("GLOB_NAME=Local\n"), 0644))
+	t.Setenv("GLOB_NAME", "")
+	t.Setenv("GLOB_VERSION", "")
+
+	err := goconfig.NewGoConfig().LoadEnv(filepath.Join(dir, "*.env"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Local", os.Getenv("GLOB_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("GLOB_VERSION"))
+}
+
+func TestLoadEnvFailGlobPatternWithoutMatch(t *testing.T) {
+	err := goconfig.NewGoConfig().LoadEnv(filepath.Join(t.TempDir(), "*.env"))
+	assert.ErrorIs(t, err, goconfig.ErrOpeningEnvFile)
+
+	err = goconfig.NewGoConfig().LoadEnv("[.env")
+	assert.ErrorIs(t, err, goconfig.ErrOpeningEnvFile)
+}
+
+func TestLoadEnvCascade(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".env":                  "CASCADE_NAME=Base\nCASCADE_VERSION=1.0\nCASCADE_LEVEL=info\n",
+		".env.local":            "CASCADE_NAME=Local\n",
+		".env.production.local": "CASCADE_LEVEL=warn\n",
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("CASCADE_NAME", "")
+	t.Setenv("CASCADE_VERSION", "")
+	t.Setenv("CASCADE_LEVEL", "")
+
+	config := goconfig.NewGoConfig(goconfig.WithDefaultEnvFile(filepath.Join(dir, ".env")))
+	err := config.LoadEnvCascade()
+	assert.NoError(t, err)
+	assert.Equal(t, "Local", os.Getenv("CASCADE_NAME"))
+	assert.Equal(t, "1.0", os.Getenv("CASCADE_VERSION"))
+	assert.Equal(t, "warn", os.Getenv("CASCADE_LEVEL"))
+
+	t.Setenv("DEPLOY_ENV", "")
+	config = goconfig.NewGoConfig(goconfig.WithDefaultEnvFile(filepath.Join(dir, ".env")),
+		goconfig.WithEnvCascade("DEPLOY_ENV", ".{env}.local", ""))
+	err = config.LoadEnvCascade()
+	assert.NoError(t, err)
+	assert.Equal(t, "Base", os.Getenv("CASCADE_NAME"))
+	assert.Equal(t, "info", os.Getenv("CASCADE_LEVEL"))
+}
+
+func TestUnloadEnv(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "unload.env")
+	content := "UNLOAD_EXISTING=Loaded\nUNLOAD_NEW=Loaded\nUNLOAD_NEW=Reloaded\n"
+	assert.NoError(t, os.WriteFile(envFile, []byte(content), 0644))
+	t.Setenv("UNLOAD_EXISTING", "Prior")
+	t.Setenv("UNLOAD_NEW", "")
+	assert.NoError(t, os.Unsetenv("UNLOAD_NEW"))
+
+	config := goconfig.NewGoConfig()
+	assert.NoError(t, config.LoadEnv(envFile))
+	assert.Equal(t, "Loaded", os.Getenv("UNLOAD_EXISTING"))
+	assert.Equal(t, "Reloaded", os.Getenv("UNLOAD_NEW"))
+
+	assert.NoError(t, config.UnloadEnv())
+	assert.Equal(t, "Prior", os.Getenv("UNLOAD_EXISTING"))
+	_, ok := os.LookupEnv("UNLOAD_NEW")
+	assert.False(t, ok)
+}
+
+func TestParseEnvSuccessBOMAndCRLF(t *testing.T) {
+	createEnvFile(t, "\xEF\xBB\xBFAPP_NAME=TestApp\r\n# comment\r\nAPP_VERSION=\"1.0.0\"\r\nAPP_KEY='multi\r\nline'\r\n")
+	defer removeEnvFile(t)
+
+	env, err := goconfig.NewGoConfig().ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp", "APP_VERSION": "1.0.0", "APP_KEY": "multi\nline"}, env)
+}
+
+func TestParseEnvSuccessWithEnvIncludes(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0755))
+	envFile := filepath.Join(dir, ".env")
+	content := "APP_NAME=Main\nsource shared/.env.shared\nAPP_URL=${SHARED_HOST}/api\n"
+	assert.NoError(t, os.WriteFile(envFile, []byte(content), 0644))
+	shared := "SHARED_HOST=http://shared\nAPP_NAME=Shared\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "shared", ".env.shared"), []byte(shared), 0644))
+
+	env, err := goconfig.NewGoConfig(goconfig.WithEnvIncludes(), goconfig.WithEnvFileExpansion()).ParseEnv(envFile)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"APP_NAME":    "Shared",
+		"SHARED_HOST": "http://shared",
+		"APP_URL":     "http://shared/api",
+	}, env)
+
+	_, err = goconfig.NewGoConfig().ParseEnv(envFile)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+}
+
+func TestParseEnvFailCircularEnvIncludes(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	assert.NoError(t, os.WriteFile(envFile, []byte("APP_NAME=Main\nsource .env.shared\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".env.shared"), []byte("source .env\n"), 0644))
+
+	_, err := goconfig.NewGoConfig(goconfig.WithEnvIncludes()).ParseEnv(envFile)
+	assert.ErrorIs(t, err, goconfig.ErrCircularInclude)
+	assert.ErrorContains(t, err, envFile+" -> "+filepath.Join(dir, ".env.shared")+" -> "+envFile)
+
+	_, err = goconfig.NewGoConfig(goconfig.WithEnvIncludes()).ParseEnv(filepath.Join(dir, ".env.missing"))
+	assert.ErrorIs(t, err, goconfig.ErrOpeningEnvFile)
+}
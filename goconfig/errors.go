@@ -7,8 +7,10 @@ var (
 	ErrUnmarshalling = errors.New("error unmarshalling configuration")
 	// ErrVariableNotFound is the error message for a missing environment variable.
 	ErrVariableNotFound = errors.New("environment variable not found")
-	// ErrUnsupportedExt is the error message for an unsupported extension.
+	// ErrUnsupportedExt is the error message for a configuration file with an extension without parser.
 	ErrUnsupportedExt = errors.New("unsupported extension")
+	// ErrConfigNotFound is the error message for a directory without configuration file matching the name.
+	ErrConfigNotFound = errors.New("configuration file not found")
 	// ErrReadingFile is the error message for a file reading error.
 	ErrReadingFile = errors.New("error reading file")
 	// ErrOpenDir is the error message for a directory opening error.
@@ -17,4 +19,32 @@ var (
 	ErrOpeningEnvFile = errors.New("error opening .env file")
 	// ErrInvalidEnvFormat is the error message for an invalid .env format.
 	ErrInvalidEnvFormat = errors.New("invalid .env format")
+	// ErrAmbiguousConfig is the error message for a configuration name matching more than one file.
+	ErrAmbiguousConfig = errors.New("ambiguous configuration")
+	// ErrWatchingConfig is the error message for a failure watching the configuration files.
+	ErrWatchingConfig = errors.New("error watching configuration")
+	// ErrInvalidStructure is the error message for a structure that is not a pointer to a struct or a map.
+	ErrInvalidStructure = errors.New("structure must be a non-nil pointer to a struct or a map")
+	// ErrValidation is the error message for a structure failing validation.
+	ErrValidation = errors.New("validation failed")
+	// ErrConvertingValue is the error message for a value that cannot be converted to the field type.
+	ErrConvertingValue = errors.New("error converting value")
+	// ErrMarshalling is the error message for a configuration that cannot be marshalled.
+	ErrMarshalling = errors.New("error marshalling configuration")
+	// ErrSectionNotFound is the error message for a configuration section missing from the file.
+	ErrSectionNotFound = errors.New("configuration section not found")
+	// ErrEnvNestingTooDeep is the error message for environment variable defaults nested too deeply.
+	ErrEnvNestingTooDeep = errors.New("environment variable defaults nested too deeply")
+	// ErrInsecurePermissions is the error message for a configuration or .env file readable by other users.
+	ErrInsecurePermissions = errors.New("insecure file permissions")
+	// ErrCircularInclude is the error message for configuration files including each other.
+	ErrCircularInclude = errors.New("circular include")
+	// ErrFetchingConfig is the error message for a configuration that cannot be fetched from a URL.
+	ErrFetchingConfig = errors.New("error fetching configuration")
+	// ErrTemplate is the error message for a configuration template that cannot be parsed or executed.
+	ErrTemplate = errors.New("error executing template")
+	// ErrDocumentNotFound is the error message for a document index out of the documents of a YAML file.
+	ErrDocumentNotFound = errors.New("document not found")
+	// ErrDuplicateEnvKey is the error message for a key defined more than once in a .env file.
+	ErrDuplicateEnvKey = errors.New("duplicate .env key")
 )
@@ -0,0 +1,44 @@
+package goconfig
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// osFS is the filesystem of the operating system.
+// Unlike os.DirFS, paths are used as they are, so both relative and absolute paths are allowed.
+// A leading "~" is expanded to the home directory of the user and, when baseDir is set,
+// relative paths are resolved against it.
+type osFS struct {
+	baseDir string
+}
+
+func (f osFS) Open(name string) (fs.File, error) {
+	return os.Open(f.resolve(name))
+}
+
+func (f osFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(f.resolve(name))
+}
+
+func (f osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(f.resolve(name))
+}
+
+// resolve expands a leading "~" of the path to the home directory and joins relative paths to the base directory.
+// The path is returned unchanged if the home directory cannot be determined.
+func (f osFS) resolve(name string) string {
+	if name == "~" || strings.HasPrefix(name, "~/") || strings.HasPrefix(name, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			name = filepath.Join(home, name[1:])
+		}
+	}
+
+	if f.baseDir != "" && !filepath.IsAbs(name) {
+		return filepath.Join(f.resolve(f.baseDir), name)
+	}
+
+	return name
+}
@@ -0,0 +1,324 @@
+package goconfig
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	stringType    = reflect.TypeOf("")
+	timeType      = reflect.TypeOf(time.Time{})
+	interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+	// unmarshalerTypes are the interfaces of the types decoding themselves, which are never shadowed.
+	unmarshalerTypes = []reflect.Type{
+		reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem(),
+		reflect.TypeOf((*json.Unmarshaler)(nil)).Elem(),
+		reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem(),
+	}
+)
+
+// hookKey identifies a decode hook by the type of the value read from the file and the type of the field.
+type hookKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+// hookRegistry stores the decode hooks, it is safe for concurrent use.
+type hookRegistry struct {
+	mu    sync.RWMutex
+	hooks map[hookKey]func(interface{}) (interface{}, error)
+}
+
+// newHookRegistry creates a registry with the built-in decode hooks.
+func newHookRegistry() *hookRegistry {
+	return &hookRegistry{hooks: map[hookKey]func(interface{}) (interface{}, error){
+		{from: stringType, to: durationType}: func(value interface{}) (interface{}, error) {
+			return time.ParseDuration(value.(string))
+		},
+		{from: stringType, to: timeType}: func(value interface{}) (interface{}, error) {
+			return time.Parse(time.RFC3339, value.(string))
+		},
+	}}
+}
+
+func (g goConfig) RegisterDecodeHook(from, to reflect.Type, fn func(interface{}) (interface{}, error)) {
+	g.hooks.mu.Lock()
+	defer g.hooks.mu.Unlock()
+
+	g.hooks.hooks[hookKey{from: from, to: to}] = fn
+}
+
+// get returns the decode hook converting values of type from into values of type to.
+func (r *hookRegistry) get(from, to reflect.Type) (func(interface{}) (interface{}, error), bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hook, ok := r.hooks[hookKey{from: from, to: to}]
+
+	return hook, ok
+}
+
+// isTarget checks if a decode hook converts values into the type.
+func (r *hookRegistry) isTarget(to reflect.Type) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for key := range r.hooks {
+		if key.to == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unmarshall unmarshalls the content into the structure with the unmarshalling function, applying the decode hooks.
+// If the structure holds types converted by decode hooks, the content is unmarshalled into a shadow copy of the
+// structure where those types are replaced by interface{}, so the unmarshalling function accepts any value for them,
+// and the values are then converted by the hooks while copying them back into the structure.
+func (r *hookRegistry) unmarshall(structure interface{}, content []byte,
+	unmarshall func(interface{}, []byte) error) error {
+	target := reflect.ValueOf(structure)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return unmarshall(structure, content)
+	}
+
+	shadowType, changed := r.shadowType(target.Type().Elem(), map[reflect.Type]bool{})
+	if !changed {
+		return unmarshall(structure, content)
+	}
+
+	shadow := reflect.New(shadowType)
+	if err := r.copyValue(shadow.Elem(), target.Elem(), ""); err != nil {
+		return err
+	}
+
+	if err := unmarshall(shadow.Interface(), content); err != nil {
+		return err
+	}
+
+	fresh := reflect.New(target.Type().Elem())
+	if err := r.copyValue(fresh.Elem(), shadow.Elem(), ""); err != nil {
+		return err
+	}
+
+	target.Elem().Set(fresh.Elem())
+
+	return nil
+}
+
+// shadowType returns the type with the types converted by decode hooks replaced by interface{},
+// and whether any type was replaced. Unexported struct fields are dropped from the replaced structs,
+// and the types implementing their own unmarshalling are kept as they are.
+func (r *hookRegistry) shadowType(t reflect.Type, visiting map[reflect.Type]bool) (reflect.Type, bool) {
+	if r.isTarget(t) {
+		return interfaceType, true
+	}
+
+	if visiting[t] || isUnmarshaler(t) {
+		return t, false
+	}
+
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		elem, changed := r.shadowType(t.Elem(), visiting)
+		if !changed {
+			return t, false
+		}
+
+		return containerOf(t, elem), true
+	case reflect.Struct:
+		return r.shadowStruct(t, visiting)
+	default:
+		return t, false
+	}
+}
+
+// isUnmarshaler checks if the type, or a pointer to it, implements yaml.Unmarshaler, json.Unmarshaler or
+// encoding.TextUnmarshaler.
+func isUnmarshaler(t reflect.Type) bool {
+	for _, unmarshaler := range unmarshalerTypes {
+		if t.Implements(unmarshaler) || reflect.PointerTo(t).Implements(unmarshaler) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containerOf returns a type of the same kind as t, a pointer, slice, array or map, holding elem.
+func containerOf(t, elem reflect.Type) reflect.Type {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return reflect.PointerTo(elem)
+	case reflect.Slice:
+		return reflect.SliceOf(elem)
+	case reflect.Array:
+		return reflect.ArrayOf(t.Len(), elem)
+	default:
+		return reflect.MapOf(t.Key(), elem)
+	}
+}
+
+// shadowStruct returns the struct type with the types of its exported fields shadowed.
+func (r *hookRegistry) shadowStruct(t reflect.Type, visiting map[reflect.Type]bool) (reflect.Type, bool) {
+	var fields []reflect.StructField
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldType, fieldChanged := r.shadowType(field.Type, visiting)
+		changed = changed || fieldChanged
+		field.Type = fieldType
+		fields = append(fields, field)
+	}
+
+	if !changed {
+		return t, false
+	}
+
+	return reflect.StructOf(fields), true
+}
+
+// copyValue copies src into dst, converting the values held by interfaces with the decode hooks when needed.
+func (r *hookRegistry) copyValue(dst, src reflect.Value, path string) error {
+	switch {
+	case !src.IsValid():
+		return nil
+	case src.Type().AssignableTo(dst.Type()):
+		dst.Set(src)
+		return nil
+	case src.Kind() == reflect.Interface:
+		if src.IsNil() {
+			return nil
+		}
+
+		return r.convert(dst, src.Elem(), path)
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		return r.copyPointer(dst, src, path)
+	case reflect.Struct:
+		return r.copyStruct(dst, src, path)
+	case reflect.Map:
+		return r.copyMap(dst, src, path)
+	case reflect.Slice, reflect.Array:
+		return r.copyList(dst, src, path)
+	}
+
+	if !isNumber(src.Kind()) || !isNumber(dst.Kind()) {
+		return fmt.Errorf("%w: %v: cannot convert %v to %v", ErrConvertingValue, path, src.Type(), dst.Type())
+	}
+
+	dst.Set(src.Convert(dst.Type()))
+
+	return nil
+}
+
+// isNumber checks if the kind is an integer or a float.
+func isNumber(kind reflect.Kind) bool {
+	return reflect.Int <= kind && kind <= reflect.Float64
+}
+
+// copyPointer allocates dst and copies the value pointed by src into it.
+func (r *hookRegistry) copyPointer(dst, src reflect.Value, path string) error {
+	if src.IsNil() {
+		return nil
+	}
+
+	dst.Set(reflect.New(dst.Type().Elem()))
+
+	return r.copyValue(dst.Elem(), src.Elem(), path)
+}
+
+// copyStruct copies the exported fields of src into the fields of dst with the same name.
+func (r *hookRegistry) copyStruct(dst, src reflect.Value, path string) error {
+	for i := 0; i < dst.NumField(); i++ {
+		field := dst.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if err := r.copyValue(dst.Field(i), src.FieldByName(field.Name), joinPath(path, field.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyMap copies the entries of src into a new map set into dst.
+func (r *hookRegistry) copyMap(dst, src reflect.Value, path string) error {
+	if src.IsNil() {
+		return nil
+	}
+
+	dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+	for _, key := range src.MapKeys() {
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		if err := r.copyValue(elem, src.MapIndex(key), fmt.Sprintf("%s[%v]", path, key)); err != nil {
+			return err
+		}
+
+		dst.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+// copyList copies the elements of the slice or array src into dst.
+func (r *hookRegistry) copyList(dst, src reflect.Value, path string) error {
+	if dst.Kind() == reflect.Slice {
+		if src.IsNil() {
+			return nil
+		}
+
+		dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+	}
+
+	for i := 0; i < min(dst.Len(), src.Len()); i++ {
+		if err := r.copyValue(dst.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convert sets the value into dst using the decode hook registered for their types,
+// or directly when the value is assignable or both are numbers.
+func (r *hookRegistry) convert(dst, value reflect.Value, path string) error {
+	hook, ok := r.get(value.Type(), dst.Type())
+	if !ok {
+		return r.copyValue(dst, value, path)
+	}
+
+	converted, err := hook(value.Interface())
+	if err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrConvertingValue, path, err)
+	}
+
+	result := reflect.ValueOf(converted)
+	if !result.IsValid() || !result.Type().AssignableTo(dst.Type()) {
+		return fmt.Errorf("%w: %v: decode hook returned %T instead of %v", ErrConvertingValue, path, converted,
+			dst.Type())
+	}
+
+	dst.Set(result)
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package goconfig_test
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type HooksConfig struct {
+	Server struct {
+		Timeout  time.Duration  `yaml:"timeout" json:"timeout"`
+		Interval *time.Duration `yaml:"interval" json:"interval"`
+		Started  time.Time      `yaml:"started" json:"started"`
+		Endpoint url.URL        `yaml:"endpoint" json:"endpoint"`
+		Name     string         `yaml:"name" json:"name"`
+	} `yaml:"server" json:"server"`
+	Retries map[string]time.Duration `yaml:"retries" json:"retries"`
+	Steps   []time.Duration          `yaml:"steps" json:"steps"`
+}
+
+func parseURLHook(value interface{}) (interface{}, error) {
+	parsed, err := url.Parse(value.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return *parsed, nil
+}
+
+func TestRegisterDecodeHookSuccess(t *testing.T) {
+	content := `server:
+  timeout: 30s
+  interval: 1m
+  started: "2024-09-06T10:00:00Z"
+  endpoint: https://example.com/api
+retries:
+  master: 5s
+steps: [1s, 2s]
+`
+	dir, _ := createConfigFile(t, content)
+	config := goconfig.NewGoConfig()
+	config.RegisterDecodeHook(reflect.TypeOf(""), reflect.TypeOf(url.URL{}), parseURLHook)
+
+	var hooksCfg HooksConfig
+	hooksCfg.Server.Name = "unchanged"
+	err := config.ParseConfig(&hooksCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, hooksCfg.Server.Timeout)
+	assert.Equal(t, time.Minute, *hooksCfg.Server.Interval)
+	assert.Equal(t, time.Date(2024, 9, 6, 10, 0, 0, 0, time.UTC), hooksCfg.Server.Started)
+	assert.Equal(t, "example.com", hooksCfg.Server.Endpoint.Host)
+	assert.Equal(t, "unchanged", hooksCfg.Server.Name)
+	assert.Equal(t, map[string]time.Duration{"master": 5 * time.Second}, hooksCfg.Retries)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, hooksCfg.Steps)
+}
+
+func TestRegisterDecodeHookSuccessJSON(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"server": {"timeout": "30s", "interval": 1000000000}}`
+	err := os.WriteFile(filepath.Join(dir, "app.json"), []byte(content), 0644)
+	assert.NoError(t, err)
+
+	var hooksCfg HooksConfig
+	err = goconfig.NewGoConfig().ParseConfig(&hooksCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, hooksCfg.Server.Timeout)
+	assert.Equal(t, time.Second, *hooksCfg.Server.Interval)
+}
+
+func TestRegisterDecodeHookFailConverting(t *testing.T) {
+	dir, _ := createConfigFile(t, "server:\n  timeout: thirty seconds\n")
+	config := goconfig.NewGoConfig()
+
+	hooksCfg := HooksConfig{}
+	hooksCfg.Server.Timeout = time.Second
+	err := config.ParseConfig(&hooksCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "Server.Timeout")
+	assert.Equal(t, time.Second, hooksCfg.Server.Timeout)
+
+	config.RegisterDecodeHook(reflect.TypeOf(""), reflect.TypeOf(time.Duration(0)),
+		func(interface{}) (interface{}, error) {
+			return nil, errors.New("invalid duration")
+		})
+	err = config.ParseConfigBytes(&hooksCfg, []byte("server:\n  timeout: 30s\n"))
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "invalid duration")
+}
+
+type HooksDB struct {
+	URL     string
+	Timeout time.Duration
+}
+
+func (db *HooksDB) UnmarshalYAML(node *yaml.Node) error {
+	var raw struct {
+		URL     string `yaml:"url"`
+		Timeout string `yaml:"timeout"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+
+	timeout, err := time.ParseDuration(raw.Timeout)
+	if err != nil {
+		return err
+	}
+
+	db.URL = "db://" + raw.URL
+	db.Timeout = 2 * timeout
+
+	return nil
+}
+
+func TestRegisterDecodeHookSuccessCustomUnmarshaler(t *testing.T) {
+	dir, _ := createConfigFile(t, "timeout: 30s\ndb:\n  url: localhost\n  timeout: 5s\n")
+
+	var hooksCfg struct {
+		Timeout time.Duration `yaml:"timeout"`
+		DB      HooksDB       `yaml:"db"`
+	}
+	err := goconfig.NewGoConfig().ParseConfig(&hooksCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, hooksCfg.Timeout)
+	assert.Equal(t, HooksDB{URL: "db://localhost", Timeout: 10 * time.Second}, hooksCfg.DB)
+}
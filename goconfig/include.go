@@ -0,0 +1,316 @@
+package goconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeKey is the top-level key listing the files included by a configuration file.
+const includeKey = "include"
+
+// expandIncludes returns the files included by the file, recursively and in order, followed by the file itself,
+// so they are deep-merged before it and the file takes precedence. The included paths are relative to the directory
+// of the file including them, and the include key is removed from the content of the files returned. It returns an
+// error wrapping ErrCircularInclude if a file includes itself, directly or through other files.
+func (g goConfig) expandIncludes(fsys fs.FS, file configFile, chain []string) ([]configFile, error) {
+	filePath := path.Clean(file.path)
+	if slices.Contains(chain, filePath) {
+		return nil, fmt.Errorf(formatError, ErrCircularInclude, strings.Join(append(chain, filePath), " -> "))
+	}
+
+	includes, found, err := g.includes(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if found {
+		if file.content, err = withoutInclude(file.content, file.extension); err != nil {
+			return nil, err
+		}
+	}
+
+	chain = append(chain, filePath)
+	var files []configFile
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = path.Join(path.Dir(filePath), include)
+		}
+
+		content, err := g.readFileFS(fsys, include)
+		if err != nil {
+			return nil, err
+		}
+
+		extension := strings.TrimPrefix(path.Ext(include), ".")
+		included, err := g.expandIncludes(fsys, configFile{path: include, extension: extension, content: content},
+			slices.Clip(chain))
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, included...)
+	}
+
+	return append(files, file), nil
+}
+
+// expandAllIncludes expands the includes of every file like expandIncludes, keeping the files in order.
+func (g goConfig) expandAllIncludes(fsys fs.FS, files []configFile) ([]configFile, error) {
+	var expanded []configFile
+	for _, file := range files {
+		included, err := g.expandIncludes(fsys, file, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, included...)
+	}
+
+	return expanded, nil
+}
+
+// includes returns the files listed by the include key of the file, a single path or a list of paths,
+// and whether the file has the include key.
+// The key is looked for with the built-in parser of the extension, the files not mentioning it being skipped, and
+// never with a custom unmarshaller, which may only handle the structures of the caller: with WithUnmarshaller,
+// includes are not resolved, and files of the formats without a built-in parser cannot include files.
+func (g goConfig) includes(file configFile) ([]string, bool, error) {
+	unmarshall, ok := builtinParsers[normalizeExtension(file.extension)]
+	if g.unmarshallFunc != nil || !ok || !bytes.Contains(file.content, []byte(includeKey)) {
+		return nil, false, nil
+	}
+
+	values := make(map[string]interface{})
+	if err := unmarshall(&values, file.content); err != nil {
+		return nil, false, err
+	}
+
+	include, found := values[includeKey]
+	switch include := include.(type) {
+	case nil:
+		return nil, found, nil
+	case string:
+		return []string{include}, true, nil
+	case []interface{}:
+		includes := make([]string, len(include))
+		for i, value := range include {
+			name, ok := value.(string)
+			if !ok {
+				return nil, false, fmt.Errorf("%w: %v: include must list file paths", ErrUnmarshalling, file.path)
+			}
+
+			includes[i] = name
+		}
+
+		return includes, true, nil
+	default:
+		return nil, false, fmt.Errorf("%w: %v: include must list file paths", ErrUnmarshalling, file.path)
+	}
+}
+
+// withoutInclude returns the YAML, JSON or TOML content without its top-level include key, so the strict parsers
+// do not report it as an unknown field. The key is blanked out rather than removed, keeping the lines and columns
+// reported by the parse errors. The content of the other formats is returned as it is.
+func withoutInclude(content []byte, extension string) ([]byte, error) {
+	switch normalizeExtension(extension) {
+	case "yaml", "yml":
+		return withoutYAMLInclude(content)
+	case "json":
+		return withoutJSONInclude(content), nil
+	case "toml":
+		return withoutTOMLInclude(content), nil
+	default:
+		return content, nil
+	}
+}
+
+// withoutYAMLInclude blanks the lines of the include key of the top-level block mapping of the YAML content,
+// up to the next key. A flow mapping, e.g. {include: base.yaml}, is marshalled again without the key instead.
+func withoutYAMLInclude(content []byte) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf(formatError, ErrUnmarshalling, err)
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return content, nil
+	}
+
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != includeKey {
+			continue
+		}
+
+		if mapping.Style&yaml.FlowStyle != 0 {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+			marshalled, err := yaml.Marshal(&document)
+			if err != nil {
+				return nil, fmt.Errorf(formatError, ErrUnmarshalling, err)
+			}
+
+			return marshalled, nil
+		}
+
+		end := 0
+		if i+2 < len(mapping.Content) {
+			end = mapping.Content[i+2].Line
+		}
+
+		return blankYAMLLines(content, mapping.Content[i].Line, end), nil
+	}
+
+	return content, nil
+}
+
+// blankYAMLLines empties the lines of the content from the line start to the line before end, both counted from 1.
+// If end is 0, the lines are emptied up to the end of the first document.
+func blankYAMLLines(content []byte, start, end int) []byte {
+	lines := bytes.Split(content, []byte("\n"))
+	if end == 0 {
+		end = len(lines) + 1
+		for i := start; i < len(lines); i++ {
+			if bytes.HasPrefix(lines[i], []byte("---")) || bytes.HasPrefix(lines[i], []byte("...")) {
+				end = i + 1
+				break
+			}
+		}
+	}
+
+	for i := start - 1; i < end-1; i++ {
+		lines[i] = nil
+	}
+
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// withoutJSONInclude blanks the include member of the top-level JSON object, with the comma separating it from
+// the other members. Invalid content is returned as it is, so the parser reports the error.
+func withoutJSONInclude(content []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return content
+	}
+
+	for first := true; decoder.More(); first = false {
+		start := int(decoder.InputOffset())
+		key, err := decoder.Token()
+		if err != nil {
+			return content
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return content
+		}
+
+		if key != includeKey {
+			continue
+		}
+
+		end := int(decoder.InputOffset())
+		if first {
+			if next := bytes.TrimLeft(content[end:], " \t\r\n"); len(next) > 0 && next[0] == ',' {
+				end = len(content) - len(next) + 1
+			}
+		}
+
+		return blankBytes(content, start, end)
+	}
+
+	return content
+}
+
+// withoutTOMLInclude blanks the lines of the include key of the TOML content, which must come before the first
+// table to be a top-level key.
+func withoutTOMLInclude(content []byte) []byte {
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		trimmed := bytes.TrimSpace(line)
+		if bytes.HasPrefix(trimmed, []byte("[")) {
+			return content
+		}
+
+		key, value, ok := bytes.Cut(trimmed, []byte("="))
+		if !ok || string(bytes.TrimSpace(key)) != includeKey {
+			continue
+		}
+
+		end := i
+		for depth := bracketDepth(value); depth > 0 && end+1 < len(lines); {
+			end++
+			depth += bracketDepth(lines[end])
+		}
+
+		for j := i; j <= end; j++ {
+			lines[j] = nil
+		}
+
+		return bytes.Join(lines, []byte("\n"))
+	}
+
+	return content
+}
+
+// bracketDepth returns the number of square brackets opened and not closed by the TOML line,
+// ignoring the brackets of strings and comments.
+func bracketDepth(line []byte) int {
+	depth := 0
+	var quote byte
+	for _, char := range line {
+		switch {
+		case quote != 0:
+			if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			quote = char
+		case char == '#':
+			return depth
+		case char == '[':
+			depth++
+		case char == ']':
+			depth--
+		}
+	}
+
+	return depth
+}
+
+// blankBytes replaces the bytes of the content from start to end by spaces, keeping the line breaks.
+func blankBytes(content []byte, start, end int) []byte {
+	blanked := slices.Clone(content)
+	for i := start; i < end; i++ {
+		if blanked[i] != '\n' {
+			blanked[i] = ' '
+		}
+	}
+
+	return blanked
+}
+
+// deleteInclude removes the include key from a map target, as the content of every format but YAML, JSON and TOML
+// keeps the key. With a custom unmarshaller, includes are not resolved and the key is kept.
+func (g goConfig) deleteInclude(structure interface{}) {
+	if g.unmarshallFunc != nil {
+		return
+	}
+
+	value := reflect.ValueOf(structure)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Map && !value.IsNil() && value.Type().Key().Kind() == reflect.String {
+		value.SetMapIndex(reflect.ValueOf(includeKey).Convert(value.Type().Key()), reflect.Value{})
+	}
+}
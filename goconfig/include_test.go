@@ -0,0 +1,197 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseConfigSuccessInclude(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":     "include: [shared/base.yaml]\nApp:\n  name: AppName\n",
+		"secrets.json": `{"storage": {"master": {"password": "secret"}}}`,
+	})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "shared"), 0755))
+	err := os.WriteFile(filepath.Join(dir, "shared", "base.yaml"), []byte(`include: ../secrets.json
+App:
+  name: BaseName
+  log_level: info
+storage:
+  master:
+    host: master-pg.localhost
+`), 0644)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	paths, err := goconfig.NewGoConfig().ParseConfigWithPaths(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, "info", yamlCfg.App.LogLevel)
+	assert.Equal(t, "master-pg.localhost", yamlCfg.Storage["master"].Host)
+	assert.Equal(t, "secret", yamlCfg.Storage["master"].Password)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "secrets.json"),
+		filepath.Join(dir, "shared", "base.yaml"),
+		filepath.Join(dir, "app.yaml"),
+	}, paths)
+}
+
+func TestParseConfigFailCircularInclude(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":   "include: [base.yaml]\nApp:\n  name: AppName\n",
+		"base.yaml":  "include: [other.yaml]\n",
+		"other.yaml": "include: [app.yaml]\n",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrCircularInclude)
+	assert.ErrorContains(t, err, "app.yaml -> "+filepath.Join(dir, "base.yaml"))
+}
+
+func TestParseConfigFailIncludeNotFound(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": "include: [missing.yaml]\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrReadingFile)
+}
+
+func TestParseConfigFailInvalidInclude(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": "include: {file: base.yaml}\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestParseConfigSuccessIncludeStrict(t *testing.T) {
+	tests := map[string]map[string]string{
+		"yaml": {
+			"app.yaml":  "include: base.yaml\nApp:\n  name: AppName\n",
+			"base.yaml": "App:\n  version: 1.0.0\n",
+		},
+		"json": {
+			"app.json":  `{"App": {"name": "AppName"}, "include": ["base.json"]}`,
+			"base.json": `{"include": [], "App": {"version": "1.0.0"}}`,
+		},
+	}
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := createConfigFiles(t, files)
+
+			var yamlCfg AppConfig
+			err := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal()).ParseConfig(&yamlCfg, "app", dir)
+			assert.NoError(t, err)
+			assert.Equal(t, App{Name: "AppName", Version: "1.0.0"}, yamlCfg.App)
+		})
+	}
+
+	dir := createConfigFiles(t, map[string]string{
+		"app.toml":  "include = [\n  \"base.toml\", # shared values\n]\n\n[App]\nname = \"AppName\"\n",
+		"base.toml": "[App]\nversion = \"1.0.0\"\n",
+	})
+
+	var tomlCfg TOMLConfig
+	err := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal()).ParseConfig(&tomlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", tomlCfg.App.Name)
+	assert.Equal(t, "1.0.0", tomlCfg.App.Version)
+}
+
+func TestParseConfigSuccessIncludeMap(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":  "App:\n  name: AppName\ninclude:\n  - base.ini\n",
+		"base.ini":  "include = other.ini\n\n[App]\nversion = 1.0.0\n",
+		"other.ini": "[App]\nlog_level = info\n",
+	})
+
+	values := map[string]interface{}{}
+	err := goconfig.NewGoConfig().ParseConfig(&values, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"App": map[string]interface{}{"name": "AppName", "version": "1.0.0", "log_level": "info"},
+	}, values)
+}
+
+func TestParseConfigSuccessIncludeEveryPath(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":             "include: base.yaml\nApp:\n  name: AppName\n",
+		"app.production.yaml":  "include: production-base.yaml\nApp:\n  log_level: warn\n",
+		"base.yaml":            "App:\n  version: 1.0.0\n",
+		"production-base.yaml": "App:\n  version: 2.0.0\n",
+	})
+	config := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal())
+
+	var profileCfg AppConfig
+	err := config.ParseConfigProfile(&profileCfg, "app", "production", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, App{Name: "AppName", Version: "2.0.0", LogLevel: "warn"}, profileCfg.App)
+
+	var recursiveCfg AppConfig
+	err = config.ParseConfigRecursive(&recursiveCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, App{Name: "AppName", Version: "1.0.0"}, recursiveCfg.App)
+
+	dirCfg := map[string]interface{}{}
+	err = config.ParseConfigDir(&dirCfg, dir)
+	assert.NoError(t, err)
+	assert.NotContains(t, dirCfg, "include")
+
+	docDir := createConfigFiles(t, map[string]string{
+		"app.yaml":  "App:\n  name: First\n---\ninclude: base.yaml\nApp:\n  name: Second\n",
+		"base.yaml": "App:\n  version: 1.0.0\n",
+	})
+	var documentCfg AppConfig
+	err = config.ParseConfigDocument(&documentCfg, "app", 1, docDir)
+	assert.NoError(t, err)
+	assert.Equal(t, App{Name: "Second", Version: "1.0.0"}, documentCfg.App)
+}
+
+func TestParseConfigFailIncludeKeepsErrorLine(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":  "include:\n  - base.yaml\nApp:\n  version: [1]\n",
+		"base.yaml": "App:\n  name: AppName\n",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	var unmarshalErr *goconfig.UnmarshalError
+	assert.ErrorAs(t, err, &unmarshalErr)
+	assert.Equal(t, 4, unmarshalErr.Line)
+}
+
+func TestParseConfigSuccessIncludeCustomUnmarshaller(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":   "App:\n  name: include-me\n",
+		"other.yaml": "include: base.yaml\nApp:\n  name: Other\n",
+	})
+	// The unmarshaller only handles the structure of the caller, like many custom unmarshallers.
+	config := goconfig.NewGoConfig(goconfig.WithUnmarshaller(func(structure interface{}, content []byte) error {
+		return yaml.Unmarshal(content, structure.(*AppConfig))
+	}))
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "include-me", yamlCfg.App.Name)
+
+	err = config.ParseConfig(&yamlCfg, "other", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Other", yamlCfg.App.Name)
+}
+
+func TestParseConfigSuccessIncludeInValue(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml": "App:\n  name: include-me\n  log_level: \"include: base.yaml\"\n",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal()).ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, App{Name: "include-me", LogLevel: "include: base.yaml"}, yamlCfg.App)
+}
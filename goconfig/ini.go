@@ -0,0 +1,66 @@
+package goconfig
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshallINI unmarshalls the INI content into the structure.
+// It is registered for the "ini" extension. Sections are mapped to nested structs or maps and their keys to the
+// fields, e.g. host=localhost in the section [storage.master] sets the field tagged `yaml:"host"` of the "master"
+// entry of the field tagged `yaml:"storage"`. Lines starting with ";" or "#" are comments.
+func UnmarshallINI(structure interface{}, content []byte) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	var section []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if name, ok := iniSection(line); ok {
+			section = splitINIKey(name)
+			continue
+		}
+
+		key, value := splitProperty(line)
+		keys := append(section[:len(section):len(section)], splitINIKey(key)...)
+		if err := setProperty(root, keys, unquoteINIValue(strings.TrimSpace(value))); err != nil {
+			return fmt.Errorf(formatError, ErrUnmarshalling, err)
+		}
+	}
+
+	return decodeNode(root, structure, scanner.Err())
+}
+
+// iniSection returns the name of the section if the line is a section header, e.g. [storage.master].
+func iniSection(line string) (string, bool) {
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+// splitINIKey splits a dotted section name or key into its parts, an empty name has no parts.
+func splitINIKey(name string) []string {
+	if name == "" {
+		return nil
+	}
+
+	return strings.Split(name, ".")
+}
+
+// unquoteINIValue removes one pair of surrounding double quotes from the value.
+func unquoteINIValue(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
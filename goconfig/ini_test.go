@@ -0,0 +1,66 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshallINISuccess(t *testing.T) {
+	content := `; Application
+[App]
+name = AppName
+version = "1.0"
+
+# Storage
+[storage.master]
+name=MASTER_CONNECTION
+host=master-pg.localhost
+port=5432
+
+[storage.slave]
+name=SLAVE_CONNECTION
+host=slave-pg.localhost
+port=5433
+`
+
+	var cfg AppConfig
+	err := goconfig.UnmarshallINI(&cfg, []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", cfg.App.Name)
+	assert.Equal(t, "1.0", cfg.App.Version)
+	assert.Equal(t, "MASTER_CONNECTION", cfg.Storage["master"].Name)
+	assert.Equal(t, "master-pg.localhost", cfg.Storage["master"].Host)
+	assert.Equal(t, 5432, cfg.Storage["master"].Port)
+	assert.Equal(t, "SLAVE_CONNECTION", cfg.Storage["slave"].Name)
+	assert.Equal(t, 5433, cfg.Storage["slave"].Port)
+}
+
+func TestUnmarshallINISuccessKeysWithoutSection(t *testing.T) {
+	var cfg AppConfig
+	err := goconfig.UnmarshallINI(&cfg, []byte("App.name=AppName\n[storage.master]\nhost=localhost\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", cfg.App.Name)
+	assert.Equal(t, "localhost", cfg.Storage["master"].Host)
+}
+
+func TestUnmarshallINIFailInvalidValue(t *testing.T) {
+	var cfg AppConfig
+	err := goconfig.UnmarshallINI(&cfg, []byte("[storage.master]\nport=eighty\n"))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestParseConfigSuccessINI(t *testing.T) {
+	t.Setenv("PG_HOST", "master-pg.localhost")
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.ini"), []byte("[storage.master]\nhost=${PG_HOST}\n"), 0644)
+	assert.NoError(t, err)
+
+	var cfg AppConfig
+	err = goconfig.NewGoConfig().ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "master-pg.localhost", cfg.Storage["master"].Host)
+}
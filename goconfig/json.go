@@ -0,0 +1,153 @@
+package goconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// UnmarshallJSON unmarshalls the JSON content into the structure using encoding/json,
+// so the fields are matched using their `json` tags. It is registered for the "json" extension.
+// Syntax and type errors include the line and column of the offending value.
+func UnmarshallJSON(structure interface{}, content []byte) error {
+	return decodeJSON(structure, content, false)
+}
+
+// UnmarshallLenientJSON unmarshalls the JSON content into the structure like UnmarshallJSON, but accepts the
+// line and block comments, "//" and "/* */", and the trailing commas of hand-edited files. It is registered for
+// the "json5" extension, the other JSON5 extensions like unquoted keys are not supported.
+func UnmarshallLenientJSON(structure interface{}, content []byte) error {
+	return UnmarshallJSON(structure, stripJSONExtensions(content))
+}
+
+// unmarshallJSONStrict unmarshalls the JSON content into the structure like UnmarshallJSON,
+// but returns an error naming the keys that do not match any field of the structure.
+func unmarshallJSONStrict(structure interface{}, content []byte) error {
+	return decodeJSON(structure, content, true)
+}
+
+// decodeJSON decodes the JSON content into the structure, optionally disallowing unknown fields.
+func decodeJSON(structure interface{}, content []byte, strict bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(structure); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalling, jsonErrorPosition(content, err))
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %w", ErrUnmarshalling, positionError(content, decoder.InputOffset(),
+			errors.New("invalid character after top-level value")))
+	}
+
+	return nil
+}
+
+// stripJSONExtensions returns a copy of the content where the comments and the trailing commas are replaced
+// by spaces, newlines excepted, so the positions of the errors are kept.
+func stripJSONExtensions(content []byte) []byte {
+	stripped := bytes.Clone(content)
+	lastComma := -1
+	for i := 0; i < len(stripped); i++ {
+		switch c := stripped[i]; {
+		case c == '"':
+			i = jsonStringEnd(stripped, i)
+			lastComma = -1
+		case c == '/' && i+1 < len(stripped) && (stripped[i+1] == '/' || stripped[i+1] == '*'):
+			i = blankJSONComment(stripped, i)
+		case c == ',':
+			lastComma = i
+		case (c == '}' || c == ']') && lastComma >= 0:
+			stripped[lastComma] = ' '
+			lastComma = -1
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			lastComma = -1
+		}
+	}
+
+	return stripped
+}
+
+// jsonStringEnd returns the index of the quote closing the string starting at the index.
+func jsonStringEnd(content []byte, start int) int {
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+
+	return len(content)
+}
+
+// blankJSONComment replaces by spaces the comment starting at the index, newlines excepted,
+// and returns the index of its last byte.
+func blankJSONComment(content []byte, start int) int {
+	block := content[start+1] == '*'
+	end := len(content)
+	if block {
+		if closing := bytes.Index(content[start+2:], []byte("*/")); closing >= 0 {
+			end = start + 2 + closing + 2
+		}
+	} else if newline := bytes.IndexByte(content[start:], '\n'); newline >= 0 {
+		end = start + newline
+	}
+
+	for i := start; i < end; i++ {
+		if content[i] != '\n' {
+			content[i] = ' '
+		}
+	}
+
+	return end - 1
+}
+
+// jsonErrorPosition adds the line and column of the syntax and type errors to them.
+func jsonErrorPosition(content []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return positionError(content, syntaxErr.Offset, err)
+	case errors.As(err, &typeErr):
+		return positionError(content, typeErr.Offset, err)
+	default:
+		return err
+	}
+}
+
+// jsonPositionError is a JSON error with the line and column of the last byte read before the failure.
+type jsonPositionError struct {
+	line   int
+	column int
+	err    error
+}
+
+// Error returns the error prefixed with its line and column.
+func (e *jsonPositionError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.line, e.column, e.err)
+}
+
+// Unwrap returns the error of the JSON decoder.
+func (e *jsonPositionError) Unwrap() error {
+	return e.err
+}
+
+// positionError returns the error with the line and column of the last byte read before the offset.
+func positionError(content []byte, offset int64, err error) error {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := max(len(before)-bytes.LastIndexByte(before, '\n')-1, 1)
+
+	return &jsonPositionError{line: line, column: column, err: err}
+}
@@ -0,0 +1,126 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type JSONConfig struct {
+	App struct {
+		Name     string `json:"name"`
+		LogLevel string `json:"log_level"`
+	} `json:"app"`
+	Storage map[string]struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+		ID   int64  `json:"id"`
+	} `json:"storage"`
+}
+
+func TestUnmarshallJSONSuccess(t *testing.T) {
+	content := `{
+  "app": {"name": "AppName", "log_level": "debug"},
+  "storage": {"master": {"host": "master-pg.localhost", "port": 5432, "id": 9007199254740993}}
+}`
+
+	var cfg JSONConfig
+	err := goconfig.UnmarshallJSON(&cfg, []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", cfg.App.Name)
+	assert.Equal(t, "debug", cfg.App.LogLevel)
+	assert.Equal(t, 5432, cfg.Storage["master"].Port)
+	assert.Equal(t, int64(9007199254740993), cfg.Storage["master"].ID)
+}
+
+func TestUnmarshallJSONFailSyntaxError(t *testing.T) {
+	content := "{\n  \"app\": {\n    \"name\": \"AppName\",\n  }\n}"
+
+	var cfg JSONConfig
+	err := goconfig.UnmarshallJSON(&cfg, []byte(content))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, "line 4, column 3")
+}
+
+func TestUnmarshallJSONFailTypeError(t *testing.T) {
+	content := "{\n  \"storage\": {\"master\": {\"port\": \"5432\"}}\n}"
+
+	var cfg JSONConfig
+	err := goconfig.UnmarshallJSON(&cfg, []byte(content))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, "line 2, column")
+	assert.ErrorContains(t, err, "storage.master.port")
+}
+
+func TestUnmarshallJSONFailTrailingData(t *testing.T) {
+	var cfg JSONConfig
+	err := goconfig.UnmarshallJSON(&cfg, []byte(`{"app": {"name": "AppName"}} {}`))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestParseConfigSuccessJSON(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"app": {"name": "${APP_NAME}"}}`), 0644)
+	assert.NoError(t, err)
+
+	var cfg JSONConfig
+	err = goconfig.NewGoConfig().ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", cfg.App.Name)
+
+	err = os.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"app": {"nmae": "TestApp"}}`), 0644)
+	assert.NoError(t, err)
+
+	err = goconfig.NewGoConfig(goconfig.WithStrictUnmarshal()).ParseConfig(&cfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, `unknown field "nmae"`)
+}
+
+const lenientJSON = `{
+  // application settings
+  "app": {"name": "App // not a comment, /* nor this */", "log_level": "debug",},
+  /* storage
+     settings */
+  "storage": {
+    "master": {"host": "master-pg.localhost", "port": 5432,}, // trailing comma
+  },
+}`
+
+func TestUnmarshallLenientJSONSuccess(t *testing.T) {
+	var cfg JSONConfig
+	err := goconfig.UnmarshallLenientJSON(&cfg, []byte(lenientJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "App // not a comment, /* nor this */", cfg.App.Name)
+	assert.Equal(t, "debug", cfg.App.LogLevel)
+	assert.Equal(t, 5432, cfg.Storage["master"].Port)
+
+	err = goconfig.UnmarshallJSON(&cfg, []byte(lenientJSON))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestUnmarshallLenientJSONFailSyntaxError(t *testing.T) {
+	var cfg JSONConfig
+	err := goconfig.UnmarshallLenientJSON(&cfg, []byte("{\n  // comment\n  \"app\": ,\n}"))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, "line 4")
+}
+
+func TestWithLenientJSON(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.json": lenientJSON, "other.json5": lenientJSON})
+
+	var cfg JSONConfig
+	err := goconfig.NewGoConfig().ParseConfig(&cfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+
+	err = goconfig.NewGoConfig().ParseConfig(&cfg, "other", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", cfg.App.LogLevel)
+
+	err = goconfig.NewGoConfig(goconfig.WithLenientJSON()).ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "master-pg.localhost", cfg.Storage["master"].Host)
+}
@@ -0,0 +1,63 @@
+package goconfig
+
+import "strings"
+
+const (
+	// EventConfigScan is logged with the "dir" and "files" fields when a directory is scanned.
+	EventConfigScan = "config.scan"
+	// EventConfigMatch is logged with the "path" and "extension" fields when a configuration file is matched.
+	EventConfigMatch = "config.match"
+	// EventEnvSubstitute is logged with the "key", "value" and "found" fields when a ${VAR} reference is resolved.
+	EventEnvSubstitute = "env.substitute"
+	// EventEnvLoad is logged with the "file" field when a .env file is loaded.
+	EventEnvLoad = "env.load"
+	// EventEnvVariable is logged with the "key" and "value" fields for every variable parsed from a .env file.
+	EventEnvVariable = "env.variable"
+
+	// maskedValue replaces the values of the sensitive keys in the logged fields.
+	maskedValue = "******"
+)
+
+// sensitiveKeys are the parts of a variable name that mark its value as sensitive.
+var sensitiveKeys = []string{"PASSWORD", "SECRET", "TOKEN"}
+
+// Logger receives the events of the loading process with their fields.
+// The values of the variables whose name contains PASSWORD, SECRET or TOKEN are masked.
+type Logger func(event string, fields map[string]interface{})
+
+// log calls the logger, if any, with the event and its fields.
+func (g goConfig) log(event string, fields map[string]interface{}) {
+	if g.logger != nil {
+		g.logger(event, fields)
+	}
+}
+
+// logLookup wraps lookup to log every variable resolved.
+func (g goConfig) logLookup(lookup func(key string) (string, bool)) func(key string) (string, bool) {
+	if g.logger == nil {
+		return lookup
+	}
+
+	return func(key string) (string, bool) {
+		value, ok := lookup(key)
+		g.log(EventEnvSubstitute, map[string]interface{}{
+			"key":   key,
+			"value": maskValue(key, value),
+			"found": ok,
+		})
+
+		return value, ok
+	}
+}
+
+// maskValue returns the value, or a mask if the key looks sensitive.
+func maskValue(key, value string) string {
+	upperKey := strings.ToUpper(key)
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(upperKey, sensitiveKey) {
+			return maskedValue
+		}
+	}
+
+	return value
+}
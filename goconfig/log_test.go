@@ -0,0 +1,67 @@
+package goconfig_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	event  string
+	fields map[string]interface{}
+}
+
+func newTestLogger() (goconfig.Logger, *[]logEntry) {
+	var entries []logEntry
+	logger := func(event string, fields map[string]interface{}) {
+		entries = append(entries, logEntry{event: event, fields: fields})
+	}
+
+	return logger, &entries
+}
+
+func TestWithLoggerParseConfig(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	t.Setenv("DB_PASSWORD", "s3cr3t")
+	dir, file := createConfigFile(t, "App:\n  name: ${APP_NAME}\n  version: ${DB_PASSWORD}\n")
+	logger, entries := newTestLogger()
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig(goconfig.WithLogger(logger))
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []logEntry{
+		{event: goconfig.EventConfigScan, fields: map[string]interface{}{"dir": dir, "files": []string{file}}},
+		{event: goconfig.EventConfigMatch, fields: map[string]interface{}{
+			"path": filepath.Join(dir, file), "extension": "yaml",
+		}},
+		{event: goconfig.EventEnvSubstitute, fields: map[string]interface{}{
+			"key": "APP_NAME", "value": "TestApp", "found": true,
+		}},
+		{event: goconfig.EventEnvSubstitute, fields: map[string]interface{}{
+			"key": "DB_PASSWORD", "value": "******", "found": true,
+		}},
+	}, *entries)
+}
+
+func TestWithLoggerLoadEnv(t *testing.T) {
+	t.Setenv("APP_NAME", "")
+	t.Setenv("API_TOKEN", "")
+	createEnvFile(t, "APP_NAME=TestApp\napi_token=abc123\n")
+	defer removeEnvFile(t)
+	logger, entries := newTestLogger()
+
+	config := goconfig.NewGoConfig(goconfig.WithLogger(logger))
+	env, err := config.ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", env["api_token"])
+
+	assert.Equal(t, []logEntry{
+		{event: goconfig.EventEnvLoad, fields: map[string]interface{}{"file": ".env"}},
+		{event: goconfig.EventEnvVariable, fields: map[string]interface{}{"key": "APP_NAME", "value": "TestApp"}},
+		{event: goconfig.EventEnvVariable, fields: map[string]interface{}{"key": "api_token", "value": "******"}},
+	}, *entries)
+}
@@ -0,0 +1,111 @@
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const tagSecret = "secret"
+
+func (g goConfig) Marshal(structure interface{}) ([]byte, error) {
+	if g.redactSecrets {
+		redacted := deepCopy(reflect.ValueOf(structure))
+		if redacted.IsValid() {
+			redactFields(redacted)
+			structure = redacted.Interface()
+		}
+	}
+
+	content, err := yaml.Marshal(structure)
+	if err != nil {
+		return nil, fmt.Errorf(formatError, ErrMarshalling, err)
+	}
+
+	return content, nil
+}
+
+// redactFields masks the fields tagged with `secret:"true"` of the struct held by the value:
+// strings, or pointers to them, are replaced by a mask and other types by their zero value.
+func redactFields(value reflect.Value) {
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct || !value.CanSet() {
+		return
+	}
+
+	_ = walkFields(value, "", func(field reflect.Value, structField reflect.StructField, _ string) error {
+		if structField.Tag.Get(tagSecret) != "true" {
+			return nil
+		}
+
+		target := field
+		for target.Kind() == reflect.Pointer && !target.IsNil() {
+			target = target.Elem()
+		}
+
+		if target.Kind() == reflect.String {
+			target.SetString(maskedValue)
+		} else {
+			field.SetZero()
+		}
+
+		return nil
+	})
+}
+
+// deepCopy returns a copy of the value that shares no pointer, map or slice with it,
+// so the copy can be modified without modifying the value. Unexported struct fields are copied shallowly.
+func deepCopy(value reflect.Value) reflect.Value {
+	if !value.IsValid() {
+		return value
+	}
+
+	copied := reflect.New(value.Type()).Elem()
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			copied.Set(reflect.New(value.Type().Elem()))
+			copied.Elem().Set(deepCopy(value.Elem()))
+		}
+	case reflect.Struct:
+		copied.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if value.Type().Field(i).IsExported() {
+				copied.Field(i).Set(deepCopy(value.Field(i)))
+			}
+		}
+	case reflect.Map:
+		if !value.IsNil() {
+			copied.Set(reflect.MakeMapWithSize(value.Type(), value.Len()))
+			for _, key := range value.MapKeys() {
+				copied.SetMapIndex(key, deepCopy(value.MapIndex(key)))
+			}
+		}
+	case reflect.Slice:
+		if !value.IsNil() {
+			copied.Set(reflect.MakeSlice(value.Type(), value.Len(), value.Len()))
+			copyElements(copied, value)
+		}
+	case reflect.Array:
+		copyElements(copied, value)
+	case reflect.Interface:
+		if !value.IsNil() {
+			copied.Set(deepCopy(value.Elem()))
+		}
+	default:
+		copied.Set(value)
+	}
+
+	return copied
+}
+
+// copyElements deep-copies the elements of the slice or array src into dst.
+func copyElements(dst, src reflect.Value) {
+	for i := 0; i < src.Len(); i++ {
+		dst.Index(i).Set(deepCopy(src.Index(i)))
+	}
+}
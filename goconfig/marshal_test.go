@@ -0,0 +1,64 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type SecretConfig struct {
+	Name    string `yaml:"name"`
+	Storage map[string]struct {
+		Host     string `yaml:"host"`
+		Password string `yaml:"password" secret:"true"`
+		Port     int    `yaml:"port" secret:"true"`
+	} `yaml:"storage"`
+	Token *string `yaml:"token" secret:"true"`
+}
+
+func TestMarshal(t *testing.T) {
+	config := goconfig.NewGoConfig()
+
+	var cfg SecretConfig
+	err := config.ParseConfigBytes(&cfg, []byte("name: AppName\nstorage:\n  master:\n    password: secret\n"))
+	assert.NoError(t, err)
+
+	content, err := config.Marshal(&cfg)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "name: AppName")
+	assert.Contains(t, string(content), "password: secret")
+}
+
+func TestMarshalWithRedactedSecrets(t *testing.T) {
+	config := goconfig.NewGoConfig(goconfig.WithRedactedSecrets())
+	content := `name: AppName
+storage:
+  master:
+    host: master-pg.localhost
+    password: secret
+    port: 5432
+token: abc
+`
+
+	var cfg SecretConfig
+	err := config.ParseConfigBytes(&cfg, []byte(content))
+	assert.NoError(t, err)
+
+	marshalled, err := config.Marshal(&cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, `name: AppName
+storage:
+    master:
+        host: master-pg.localhost
+        password: '******'
+        port: 0
+token: '******'
+`, string(marshalled))
+	assert.Equal(t, "secret", cfg.Storage["master"].Password)
+	assert.Equal(t, 5432, cfg.Storage["master"].Port)
+	assert.Equal(t, "abc", *cfg.Token)
+
+	_, err = config.Marshal(cfg)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,414 @@
+package goconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// mapFormats are the formats the files of each built-in format are merged in: the files are decoded into maps,
+// merged and encoded again in that format, whose struct tags apply, e.g. the INI files are merged as YAML.
+var mapFormats = map[string]string{
+	"yaml": "yaml", "yml": "yaml", "ini": "yaml", "properties": "yaml", "json": "json", "json5": "json", "toml": "toml",
+}
+
+// mergeInto unmarshalls the content into a new value of the structure type and deep-merges it into the structure.
+func (g goConfig) mergeInto(structure interface{}, content []byte, extension string) error {
+	target := reflect.ValueOf(structure)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+	}
+
+	layer := reflect.New(target.Type().Elem())
+	if err := g.unmarshall(layer.Interface(), content, extension); err != nil {
+		return err
+	}
+
+	merger{slices: g.sliceMerge}.mergeValues(target.Elem(), layer.Elem())
+
+	return nil
+}
+
+// mapFormat returns the format the files can be merged in as maps: they must all be parsed by built-in parsers of
+// formats merged in the same format, as must the format itself. It returns false otherwise, e.g. with a custom
+// unmarshaller, a parser registered with RegisterParser or YAML and JSON files, which may use different struct tags.
+func (g goConfig) mapFormat(files []configFile) (string, bool) {
+	if g.unmarshallFunc != nil || len(files) == 0 {
+		return "", false
+	}
+
+	var merged string
+	for i, file := range files {
+		format, ok := g.parsers.format(file.extension)
+		if !ok || i > 0 && mapFormats[format] != merged {
+			return "", false
+		}
+
+		merged = mapFormats[format]
+	}
+
+	if format, ok := g.parsers.format(merged); !ok || format != merged {
+		return "", false
+	}
+
+	return merged, true
+}
+
+// unmarshallMerged deep-merges the maps decoded from the files by decodeMaps in order and unmarshalls the result into
+// the structure at once, in the format returned by mapFormat. Unlike the fields of a structure, the keys of a map are
+// only present when set, so a later file replaces the values of the earlier ones even by the zero value, e.g.
+// "debug: false". Every file is unmarshalled into a new structure first, so its errors report its own lines.
+func (g goConfig) unmarshallMerged(structure interface{}, files []configFile, values []map[string]interface{},
+	format string) error {
+	if err := checkStructure(structure); err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(structure)
+	original := deepCopy(target.Elem())
+	merged := reflect.ValueOf(make(map[string]interface{}))
+	m := merger{slices: g.sliceMerge}
+	for i, file := range files {
+		if err := g.unmarshall(reflect.New(target.Type().Elem()).Interface(), file.content, file.extension); err != nil {
+			return newUnmarshalError(file.path, file.extension, err)
+		}
+
+		m.mergeMaps(merged, reflect.ValueOf(values[i]))
+
+		// With source tracking, the merged maps are unmarshalled after every file to find the values it changed.
+		if g.sources == nil && i < len(files)-1 {
+			continue
+		}
+
+		err := g.trackSources(structure, file.path, func() error {
+			content, err := encodeMap(merged.Interface().(map[string]interface{}), format)
+			if err != nil {
+				return fmt.Errorf(formatError, ErrUnmarshalling, err)
+			}
+
+			target.Elem().Set(deepCopy(original))
+
+			return g.unmarshall(structure, content, format)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeMaps decodes the content of every file into a map with the built-in parser of its format. It returns false if
+// a file cannot be decoded into a map, e.g. for a syntax error reported when unmarshalling the file into the structure.
+func (g goConfig) decodeMaps(files []configFile) ([]map[string]interface{}, bool) {
+	maps := make([]map[string]interface{}, len(files))
+	for i, file := range files {
+		format, _ := g.parsers.format(file.extension)
+		values, ok := decodeMap(file.content, format)
+		if !ok {
+			return nil, false
+		}
+
+		maps[i] = values
+	}
+
+	return maps, true
+}
+
+// decodeMap decodes the content of the built-in format into a map. The JSON content is decoded leniently, keeping its
+// numbers as json.Number so large integers are not rounded, and the scalars of the YAML content are kept as nodes, so
+// they are encoded again as they were written, e.g. "2.0" is not turned into "2".
+func decodeMap(content []byte, format string) (map[string]interface{}, bool) {
+	switch mapFormats[format] {
+	case "yaml":
+		var node yaml.Node
+		if err := builtinParsers[format](&node, content); err != nil {
+			return nil, false
+		}
+
+		if node.Kind == yaml.DocumentNode && len(node.Content) == 0 || node.Kind == 0 {
+			return map[string]interface{}{}, true
+		}
+
+		value, ok := nodeValue(&node)
+		values, isMap := value.(map[string]interface{})
+
+		return values, ok && isMap
+	case "json":
+		values := make(map[string]interface{})
+		decoder := json.NewDecoder(bytes.NewReader(stripJSONExtensions(content)))
+		decoder.UseNumber()
+
+		return values, decoder.Decode(&values) == nil
+	default:
+		values := make(map[string]interface{})
+
+		return values, builtinParsers[format](&values, content) == nil
+	}
+}
+
+// nodeValue converts the YAML node into maps and slices holding its scalar nodes, expanding the aliases and the merge
+// keys. It returns false if a mapping has a key that is not a string, which cannot be merged as a map key.
+func nodeValue(node *yaml.Node) (interface{}, bool) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.SequenceNode:
+		values := make([]interface{}, len(node.Content))
+		for i, item := range node.Content {
+			value, ok := nodeValue(item)
+			if !ok {
+				return nil, false
+			}
+
+			values[i] = value
+		}
+
+		return values, true
+	case yaml.MappingNode:
+		return mappingValues(node)
+	default:
+		return node, true
+	}
+}
+
+// mappingValues converts the YAML mapping node into a map like nodeValue. The entries of the mappings of its merge
+// keys, e.g. "<<: *defaults", are added first, so the keys of the mapping take precedence.
+func mappingValues(node *yaml.Node) (interface{}, bool) {
+	values := make(map[string]interface{}, len(node.Content)/2)
+	var keys, merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch key := node.Content[i]; {
+		case key.Kind == yaml.ScalarNode && key.ShortTag() == "!!merge":
+			merges = append(merges, node.Content[i+1])
+		case key.Kind == yaml.ScalarNode && key.ShortTag() == "!!str":
+			keys = append(keys, key, node.Content[i+1])
+		default:
+			return nil, false
+		}
+	}
+
+	for _, merge := range merges {
+		value, ok := nodeValue(merge)
+		if !ok {
+			return nil, false
+		}
+
+		mergedMaps, isList := value.([]interface{})
+		if !isList {
+			mergedMaps = []interface{}{value}
+		}
+
+		for _, mergedMap := range mergedMaps {
+			entries, isMap := mergedMap.(map[string]interface{})
+			if !isMap {
+				return nil, false
+			}
+
+			for key, entry := range entries {
+				values[key] = entry
+			}
+		}
+	}
+
+	for i := 0; i < len(keys); i += 2 {
+		value, ok := nodeValue(keys[i+1])
+		if !ok {
+			return nil, false
+		}
+
+		values[keys[i].Value] = value
+	}
+
+	return values, true
+}
+
+// encodeMap encodes the map in the format, "yaml", "json" or "toml".
+func encodeMap(values map[string]interface{}, format string) ([]byte, error) {
+	switch format {
+	case "json":
+		return json.Marshal(values)
+	case "toml":
+		var content bytes.Buffer
+		err := toml.NewEncoder(&content).Encode(values)
+
+		return content.Bytes(), err
+	default:
+		return yaml.Marshal(values)
+	}
+}
+
+// SliceMergeStrategy is the strategy merging the slices of the configuration files read from several directories.
+type SliceMergeStrategy int
+
+const (
+	// SliceReplace replaces the slice by the one of the later file, the default.
+	SliceReplace SliceMergeStrategy = iota
+	// SliceAppend appends the elements of the later file to the slice.
+	SliceAppend
+	// SliceMergeByIndex deep-merges the elements of the later file into the elements of the slice with the same
+	// index, the extra elements are appended.
+	SliceMergeByIndex
+)
+
+// merger deep-merges values, merging the slices with its strategy.
+type merger struct {
+	slices SliceMergeStrategy
+}
+
+// mergeValues deep-merges src into dst, which must be settable.
+// Structs are merged field by field, maps key by key and slices with the strategy, any other value of src replaces
+// the one of dst when it is not the zero value, so a field absent from src keeps the value of dst.
+func (m merger) mergeValues(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		if hasUnexportedFields(src.Type()) {
+			setIfNotZero(dst, src)
+			return
+		}
+
+		for i := 0; i < src.NumField(); i++ {
+			m.mergeValues(dst.Field(i), src.Field(i))
+		}
+	case reflect.Map:
+		m.mergeMaps(dst, src)
+	case reflect.Slice:
+		if m.slices == SliceReplace || dst.IsNil() {
+			setIfNotZero(dst, src)
+			return
+		}
+
+		dst.Set(m.mergeSlices(dst, src))
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+
+		if dst.IsNil() {
+			dst.Set(src)
+			return
+		}
+
+		m.mergeValues(dst.Elem(), src.Elem())
+	default:
+		setIfNotZero(dst, src)
+	}
+}
+
+// mergeMaps merges the entries of src into dst, the entries present in both maps are deep-merged.
+func (m merger) mergeMaps(dst, src reflect.Value) {
+	if src.Len() == 0 {
+		return
+	}
+
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+	}
+
+	iter := src.MapRange()
+	for iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		if existing := dst.MapIndex(key); existing.IsValid() {
+			value = m.mergeMapEntry(existing, value)
+		}
+
+		dst.SetMapIndex(key, value)
+	}
+}
+
+// mergeMapEntry returns the result of merging the value into the existing entry of a map.
+// Nested maps and structs are deep-merged, any other value replaces the existing one, even if it is the zero value,
+// since the presence of the key means it was set explicitly.
+func (m merger) mergeMapEntry(existing, value reflect.Value) reflect.Value {
+	current, next := unwrapInterface(existing), unwrapInterface(value)
+	if !current.IsValid() || !next.IsValid() || current.Type() != next.Type() {
+		return value
+	}
+
+	switch current.Kind() {
+	case reflect.Map:
+		if current.IsNil() {
+			return value
+		}
+
+		m.mergeMaps(current, next)
+
+		return existing
+	case reflect.Struct:
+		merged := reflect.New(current.Type()).Elem()
+		merged.Set(current)
+		m.mergeValues(merged, next)
+
+		return merged
+	case reflect.Slice:
+		if m.slices == SliceReplace || current.IsNil() {
+			return value
+		}
+
+		return m.mergeSlices(current, next)
+	default:
+		return value
+	}
+}
+
+// mergeSlices returns a new slice holding the elements of dst merged with the elements of src by the strategy.
+func (m merger) mergeSlices(dst, src reflect.Value) reflect.Value {
+	merged := reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len())
+	merged = reflect.AppendSlice(merged, dst)
+	if m.slices == SliceAppend {
+		return reflect.AppendSlice(merged, src)
+	}
+
+	for i := 0; i < src.Len(); i++ {
+		if i >= merged.Len() {
+			merged = reflect.Append(merged, src.Index(i))
+			continue
+		}
+
+		if src.Index(i).Kind() == reflect.Interface {
+			merged.Index(i).Set(m.mergeMapEntry(merged.Index(i), src.Index(i)))
+			continue
+		}
+
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		elem.Set(merged.Index(i))
+		m.mergeValues(elem, src.Index(i))
+		merged.Index(i).Set(elem)
+	}
+
+	return merged
+}
+
+// unwrapInterface returns the value held by an interface, or the value itself if it is not an interface.
+func unwrapInterface(value reflect.Value) reflect.Value {
+	if value.Kind() == reflect.Interface {
+		return value.Elem()
+	}
+
+	return value
+}
+
+// setIfNotZero sets src into dst when src is not the zero value.
+func setIfNotZero(dst, src reflect.Value) {
+	if !src.IsZero() {
+		dst.Set(src)
+	}
+}
+
+// hasUnexportedFields checks if the struct type has unexported fields, like time.Time.
+// Those structs cannot be merged field by field.
+func hasUnexportedFields(structType reflect.Type) bool {
+	for i := 0; i < structType.NumField(); i++ {
+		if !structType.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,345 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigMultipleDirsSuccessMerge(t *testing.T) {
+	base := `App:
+  name: AppName
+  version: 1.0
+storage:
+  master:
+    host: master-pg.localhost
+    port: 5432
+  slave:
+    host: slave-pg.localhost
+    port: 5432
+`
+	override := `App:
+  version: 2.0
+storage:
+  master:
+    host: master-pg.production
+  replica:
+    host: replica-pg.production
+`
+	baseDir, _ := createConfigFile(t, base)
+	overrideDir, _ := createConfigFile(t, override)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfig(&yamlCfg, "App", baseDir, overrideDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+	assert.Equal(t, "master-pg.production", yamlCfg.Storage["master"].Host)
+	assert.Equal(t, 5432, yamlCfg.Storage["master"].Port)
+	assert.Equal(t, "slave-pg.localhost", yamlCfg.Storage["slave"].Host)
+	assert.Equal(t, "replica-pg.production", yamlCfg.Storage["replica"].Host)
+}
+
+func TestParseConfigMultipleDirsSuccessMergeMap(t *testing.T) {
+	base := `app:
+  name: AppName
+  debug: true
+  tags: [a, b]
+`
+	override := `app:
+  debug: false
+  tags: [c]
+`
+	baseDir, _ := createConfigFile(t, base)
+	overrideDir, _ := createConfigFile(t, override)
+
+	var mapCfg map[string]interface{}
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfig(&mapCfg, "App", baseDir, overrideDir)
+	assert.NoError(t, err)
+
+	app := mapCfg["app"].(map[string]interface{})
+	assert.Equal(t, "AppName", app["name"])
+	assert.Equal(t, false, app["debug"])
+	assert.Equal(t, []interface{}{"c"}, app["tags"])
+}
+
+type ServerConfig struct {
+	Name  string `yaml:"name" json:"name" toml:"name"`
+	Debug bool   `yaml:"debug" json:"debug" toml:"debug"`
+	Port  int    `yaml:"port" json:"port" toml:"port"`
+}
+
+func TestParseConfigMultipleDirsSuccessMergeZeroValues(t *testing.T) {
+	tests := map[string][2]string{
+		"yaml": {"name: Server\ndebug: true\nport: 8080\n", "debug: false\nport: 0\n"},
+		"json": {`{"name": "Server", "debug": true, "port": 8080}`, `{"debug": false, "port": 0}`},
+		"toml": {"name = \"Server\"\ndebug = true\nport = 8080\n", "debug = false\nport = 0\n"},
+	}
+	for ext, contents := range tests {
+		t.Run(ext, func(t *testing.T) {
+			baseDir := createConfigFiles(t, map[string]string{"server." + ext: contents[0]})
+			prodDir := createConfigFiles(t, map[string]string{"server." + ext: contents[1]})
+			config := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal(), goconfig.WithSourceTracking())
+
+			var serverCfg ServerConfig
+			err := config.ParseConfig(&serverCfg, "server", baseDir, prodDir)
+			assert.NoError(t, err)
+			assert.Equal(t, ServerConfig{Name: "Server"}, serverCfg)
+			assert.Equal(t, filepath.Join(prodDir, "server."+ext), config.Sources()["Debug"])
+			assert.Equal(t, filepath.Join(baseDir, "server."+ext), config.Sources()["Name"])
+		})
+	}
+}
+
+func TestParseConfigMultipleDirsFailFileMissing(t *testing.T) {
+	baseDir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	emptyDir := t.TempDir()
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfig(&yamlCfg, "App", baseDir, emptyDir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestParseConfigMultipleDirsFailInvalidStructure(t *testing.T) {
+	baseDir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	overrideDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(overrideDir, "App.yaml"), []byte("App:\n  name: Other\n"), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig(func(structure interface{}, content []byte) error {
+		return nil
+	})
+	assert.NotNil(t, config)
+
+	err = config.ParseConfig(AppConfig{}, "App", baseDir, overrideDir)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+}
+
+func TestParseConfigProfileSuccess(t *testing.T) {
+	dir, _ := createConfigFile(t, `App:
+  name: AppName
+  version: 1.0
+storage:
+  master:
+    host: master-pg.localhost
+    port: 5432
+`)
+	profile := `App:
+  version: 2.0
+storage:
+  master:
+    host: master-pg.production
+`
+	err := os.WriteFile(filepath.Join(dir, "App.production.yaml"), []byte(profile), 0644)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err = config.ParseConfigProfile(&yamlCfg, "App", "production", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+	assert.Equal(t, "master-pg.production", yamlCfg.Storage["master"].Host)
+	assert.Equal(t, 5432, yamlCfg.Storage["master"].Port)
+}
+
+func TestParseConfigProfileSuccessFromEnv(t *testing.T) {
+	t.Setenv("APP_ENV", "staging")
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	err := os.WriteFile(filepath.Join(dir, "App.staging.yaml"), []byte("App:\n  name: StagingApp\n"), 0644)
+	assert.NoError(t, err)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err = config.ParseConfigProfile(&yamlCfg, "App", "", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "StagingApp", yamlCfg.App.Name)
+}
+
+func TestParseConfigProfileSuccessZeroValues(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":      "name: Server\ndebug: true\nport: 8080\n",
+		"app.prod.yaml": "debug: false\nport: 0\n",
+	})
+
+	var serverCfg ServerConfig
+	err := goconfig.NewGoConfig().ParseConfigProfile(&serverCfg, "app", "prod", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, ServerConfig{Name: "Server"}, serverCfg)
+}
+
+func TestParseConfigProfileSuccessProfileFileAbsent(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigProfile(&yamlCfg, "App", "production", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestParseConfigProfileFailBaseFileAbsent(t *testing.T) {
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.ParseConfigProfile(&yamlCfg, "App", "production", t.TempDir())
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestParseConfigDirSuccess(t *testing.T) {
+	t.Setenv("PG_PASSWORD", "secret")
+	dir := createConfigFiles(t, map[string]string{
+		".env":         "APP_NAME=Ignored",
+		"app.yaml":     "App:\n  name: AppName\n  version: 1.0\n",
+		"config.go":    "package config",
+		"logging.yml":  "App:\n  log_level: debug\n  version: 1.1\n",
+		"storage.yaml": "storage:\n  master:\n    host: master-pg.localhost\n    password: ${PG_PASSWORD}\n",
+	})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "prod"), 0755))
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigDir(&yamlCfg, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.Equal(t, "debug", yamlCfg.App.LogLevel)
+	assert.Equal(t, "1.1", yamlCfg.App.Version)
+	assert.Equal(t, "master-pg.localhost", yamlCfg.Storage["master"].Host)
+	assert.Equal(t, "secret", yamlCfg.Storage["master"].Password)
+}
+
+func TestParseConfigDirFailNoConfigFile(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"config.go": "package config"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigDir(&yamlCfg, dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+
+	err = goconfig.NewGoConfig().ParseConfigDir(&yamlCfg, filepath.Join(dir, "notfound"))
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestWithYAMLConcatSuccessAnchors(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"1-base.yaml":    "base: &db\n  host: pg.localhost\n  port: 5432\n",
+		"2-storage.yaml": "storage:\n  master:\n    <<: *db\n    name: MASTER_CONNECTION\n  slave: *db\n",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithYAMLConcat()).ParseConfigDir(&yamlCfg, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, Storage{Name: "MASTER_CONNECTION", Host: "pg.localhost", Port: 5432}, yamlCfg.Storage["master"])
+	assert.Equal(t, Storage{Host: "pg.localhost", Port: 5432}, yamlCfg.Storage["slave"])
+
+	err = goconfig.NewGoConfig().ParseConfigDir(&yamlCfg, dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestWithYAMLConcatSuccessMultipleDirs(t *testing.T) {
+	baseDir := createConfigFiles(t, map[string]string{"app.yaml": "defaults: &app\n  name: AppName\n"})
+	prodDir := createConfigFiles(t, map[string]string{"app.yml": "App: *app\n"})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithYAMLConcat()).ParseConfig(&yamlCfg, "app", baseDir, prodDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestWithYAMLConcatFailNotYAML(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"1-base.yaml": "App:\n  name: AppName\n",
+		"2-app.json":  `{"storage": {}}`,
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithYAMLConcat()).ParseConfigDir(&yamlCfg, dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+}
+
+type StoragesConfig struct {
+	Storages []Storage `yaml:"storages"`
+	Tags     []string  `yaml:"tags"`
+}
+
+func parseStorages(t *testing.T, strategy goconfig.SliceMergeStrategy) StoragesConfig {
+	base := `storages:
+  - name: MASTER_CONNECTION
+    host: master-pg.localhost
+    port: 5432
+tags: [base]
+`
+	override := `storages:
+  - host: master-pg.production
+  - name: SLAVE_CONNECTION
+    host: slave-pg.production
+tags: [production]
+`
+	baseDir := createConfigFiles(t, map[string]string{"app.yaml": base})
+	overrideDir := createConfigFiles(t, map[string]string{"app.yaml": override})
+
+	var cfg StoragesConfig
+	err := goconfig.NewGoConfig(goconfig.WithSliceMerge(strategy)).ParseConfig(&cfg, "app", baseDir, overrideDir)
+	assert.NoError(t, err)
+
+	return cfg
+}
+
+func TestWithSliceMergeReplace(t *testing.T) {
+	cfg := parseStorages(t, goconfig.SliceReplace)
+	assert.Equal(t, []Storage{
+		{Host: "master-pg.production"},
+		{Name: "SLAVE_CONNECTION", Host: "slave-pg.production"},
+	}, cfg.Storages)
+	assert.Equal(t, []string{"production"}, cfg.Tags)
+}
+
+func TestWithSliceMergeAppend(t *testing.T) {
+	cfg := parseStorages(t, goconfig.SliceAppend)
+	assert.Equal(t, []Storage{
+		{Name: "MASTER_CONNECTION", Host: "master-pg.localhost", Port: 5432},
+		{Host: "master-pg.production"},
+		{Name: "SLAVE_CONNECTION", Host: "slave-pg.production"},
+	}, cfg.Storages)
+	assert.Equal(t, []string{"base", "production"}, cfg.Tags)
+}
+
+func TestWithSliceMergeByIndex(t *testing.T) {
+	cfg := parseStorages(t, goconfig.SliceMergeByIndex)
+	assert.Equal(t, []Storage{
+		{Name: "MASTER_CONNECTION", Host: "master-pg.production", Port: 5432},
+		{Name: "SLAVE_CONNECTION", Host: "slave-pg.production"},
+	}, cfg.Storages)
+	assert.Equal(t, []string{"production"}, cfg.Tags)
+}
+
+func TestWithSliceMergeByIndexMap(t *testing.T) {
+	baseDir := createConfigFiles(t, map[string]string{"app.yaml": "storages:\n  - host: master\n    port: 5432\n"})
+	overrideDir := createConfigFiles(t, map[string]string{"app.yaml": "storages:\n  - host: primary\n  - host: replica\n"})
+
+	var cfg map[string]any
+	config := goconfig.NewGoConfig(goconfig.WithSliceMerge(goconfig.SliceMergeByIndex))
+	err := config.ParseConfig(&cfg, "app", baseDir, overrideDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{
+		map[string]any{"host": "primary", "port": 5432},
+		map[string]any{"host": "replica"},
+	}, cfg["storages"])
+}
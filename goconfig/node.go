@@ -0,0 +1,27 @@
+package goconfig
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (g goConfig) ParseConfigNode(fileName string, directoryName ...string) (*yaml.Node, error) {
+	file, err := g.read(g.configName(fileName), directoryName...)
+	if err != nil {
+		return nil, err
+	}
+
+	switch normalizeExtension(file.extension) {
+	case "yaml", "yml", "json":
+	default:
+		return nil, fmt.Errorf("%w: %v cannot be parsed as a YAML node", ErrUnsupportedExt, file.path)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(file.content, &node); err != nil {
+		return nil, fmt.Errorf(formatError, ErrUnmarshalling, err)
+	}
+
+	return &node, nil
+}
@@ -0,0 +1,57 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigNodeSuccessKeepsOrder(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	content := `zeta:
+    name: ${APP_NAME}
+    # comment kept
+    version: 1.0.0
+alpha:
+    port: 8080
+    host: localhost
+middle: true
+`
+	dir := createConfigFiles(t, map[string]string{"app.yaml": content})
+	config := goconfig.NewGoConfig()
+
+	node, err := config.ParseConfigNode("app", dir)
+	assert.NoError(t, err)
+
+	output, err := config.Marshal(node)
+	assert.NoError(t, err)
+	assert.Equal(t, `zeta:
+    name: TestApp
+    # comment kept
+    version: 1.0.0
+alpha:
+    port: 8080
+    host: localhost
+middle: true
+`, string(output))
+
+	values, err := config.ParseValues("app", dir)
+	assert.NoError(t, err)
+
+	output, err = config.Marshal(map[string]interface{}(values))
+	assert.NoError(t, err)
+	assert.Equal(t, "alpha:", string(output[:6]))
+}
+
+func TestParseConfigNodeFailUnsupportedExt(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.toml": "name = \"App\"\n"})
+
+	_, err := goconfig.NewGoConfig().ParseConfigNode("app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnsupportedExt)
+}
+
+func TestParseConfigNodeFailNotFound(t *testing.T) {
+	_, err := goconfig.NewGoConfig().ParseConfigNode("app", t.TempDir())
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
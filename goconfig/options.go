@@ -0,0 +1,331 @@
+package goconfig
+
+import "time"
+
+// Option configures a GoConfig instance created with NewGoConfig.
+// It is one of the values returned by the With functions of this package or, for backward compatibility,
+// an unmarshalling function func(interface{}, []byte) error, equivalent to WithUnmarshaller.
+type Option interface{}
+
+// optionFunc is the Option returned by the With functions.
+type optionFunc func(*goConfig)
+
+// WithUnmarshaller sets the unmarshalling function used for every file regardless of its extension, instead of the
+// parsers registered by extension. Passing the function itself to NewGoConfig is equivalent.
+func WithUnmarshaller(fn func(interface{}, []byte) error) Option {
+	return optionFunc(func(g *goConfig) {
+		g.unmarshallFunc = func(structure interface{}, content []byte, _ string) error {
+			return fn(structure, content)
+		}
+	})
+}
+
+// WithExcludedExtensions sets the file extensions ignored when searching a configuration file, e.g. "go", "md".
+// It replaces the default excluded extensions, which only contain "go".
+func WithExcludedExtensions(extensions ...string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.excludeExtensions = normalizeExtensions(extensions)
+	})
+}
+
+// WithAllowedExtensions sets the only file extensions considered when searching a configuration file,
+// e.g. "yaml", "json". By default every extension not excluded is considered.
+func WithAllowedExtensions(extensions ...string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.allowedExtensions = normalizeExtensions(extensions)
+	})
+}
+
+// WithValidation enables the validation of the structure after unmarshalling it.
+// Fields tagged with `validate:"required"` must not be the zero value, otherwise parsing returns an error
+// wrapping ErrValidation that names every failing field.
+func WithValidation() Option {
+	return optionFunc(func(g *goConfig) {
+		g.validate = true
+	})
+}
+
+// WithDefaults enables setting the fields still holding the zero value after unmarshalling from their
+// `default:"value"` tag, e.g. `yaml:"port" default:"5432"`. Nested structs and pointers are supported.
+func WithDefaults() Option {
+	return optionFunc(func(g *goConfig) {
+		g.defaults = true
+	})
+}
+
+// WithCaseSensitiveMatch matches the configuration file names exactly, so "app" only matches "app.yaml" and not
+// "App.yaml". By default the names are matched case-insensitively.
+func WithCaseSensitiveMatch() Option {
+	return optionFunc(func(g *goConfig) {
+		g.caseSensitive = true
+	})
+}
+
+// WithLogger sets a logger receiving the events of the loading process: the directories scanned, the files matched,
+// the .env files loaded and the environment variables resolved. The values of the variables whose name contains
+// PASSWORD, SECRET or TOKEN are masked.
+func WithLogger(logger Logger) Option {
+	return optionFunc(func(g *goConfig) {
+		g.logger = logger
+	})
+}
+
+// WithStrictUnmarshal makes the YAML, JSON and TOML parsers return an error wrapping ErrUnmarshalling that names
+// the keys of the file not matching any field of the structure, e.g. a typo like "verison".
+// The parsers registered afterwards with RegisterParser are not affected.
+func WithStrictUnmarshal() Option {
+	return optionFunc(func(g *goConfig) {
+		g.parsers.set("yaml", unmarshallYAMLStrict)
+		g.parsers.set("yml", unmarshallYAMLStrict)
+		g.parsers.set("json", unmarshallJSONStrict)
+		g.parsers.set("toml", unmarshallTOMLStrict)
+	})
+}
+
+// WithLenientJSON parses the .json files with UnmarshallLenientJSON, accepting comments and trailing commas.
+func WithLenientJSON() Option {
+	return optionFunc(func(g *goConfig) {
+		g.parsers.set("json", UnmarshallLenientJSON)
+	})
+}
+
+// WithCache enables caching the configuration parsed by ParseConfig: later calls with the same file name,
+// directories and structure type return the cached configuration without reading the files again.
+// Use Reload to read the files again. The structure receives an independent deep copy of the cached configuration,
+// so its maps and slices can be modified without affecting the cache.
+func WithCache() Option {
+	return optionFunc(func(g *goConfig) {
+		g.cache = newConfigCache()
+	})
+}
+
+// WithEnvAggregateErrors makes LoadEnv, LoadEnvIfAbsent and ParseEnv continue after an invalid line and return
+// every error found joined with errors.Join, each one prefixed with its file and line. The valid lines are still
+// loaded: ParseEnv returns them with the error, the map being nil only on errors when the option is not set.
+// By default parsing stops at the first error.
+func WithEnvAggregateErrors() Option {
+	return optionFunc(func(g *goConfig) {
+		g.envAggregateErrors = true
+	})
+}
+
+// WithEnvCommentPrefixes sets the prefixes of the comment lines of the .env files, e.g. "#", ";" or "//".
+// Leading whitespace is ignored. It replaces the default comment prefixes, which only contain "#".
+// Inline comments in values always start with "#".
+func WithEnvCommentPrefixes(prefixes ...string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.envCommentPrefixes = prefixes
+	})
+}
+
+// WithBaseDir resolves the relative directories and file paths against the base directory instead of the working
+// directory, e.g. the directory of the executable. A leading "~" is expanded to the home directory of the user
+// with or without this option.
+func WithBaseDir(dir string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.baseDir = dir
+	})
+}
+
+// WithDefaultDir sets the directory of the configuration files used when no directory is provided,
+// instead of "config".
+func WithDefaultDir(dir string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.defaultDir = dir
+	})
+}
+
+// WithDefaultEnvFile sets the .env file loaded by LoadEnv, LoadEnvIfAbsent and ParseEnv when no file is provided,
+// instead of ".env". The path may include a directory, e.g. "deploy/.env".
+func WithDefaultEnvFile(filePath string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.defaultEnvFile = filePath
+	})
+}
+
+// WithProfileFromEnv names the configuration file after the profile of the environment variable, e.g. APP_ENV,
+// when an empty file name is passed to ParseConfig, so APP_ENV=production reads production.yaml.
+// The default profile is used when the variable is not set. The variable is also used by ParseConfigProfile.
+func WithProfileFromEnv(envVar, defaultProfile string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.profileFromEnv = true
+		g.profileEnvVar = envVar
+		g.defaultProfile = defaultProfile
+	})
+}
+
+// WithEnvDelimiters sets the delimiters of the references to environment variables in the configuration files,
+// e.g. "{{" and "}}" for {{APP_NAME}} and {{APP_NAME:-default}}, leaving ${...} untouched for other templating tools.
+// A reference escaped with a leading "$", e.g. ${{APP_NAME}}, is emitted as the literal {{APP_NAME}}.
+// The .env values expanded with WithEnvFileExpansion use the same delimiters. Empty delimiters keep the default ones.
+func WithEnvDelimiters(opening, closing string) Option {
+	return optionFunc(func(g *goConfig) {
+		if opening != "" && closing != "" {
+			g.envSyntax = newEnvSyntax(opening, closing)
+		}
+	})
+}
+
+// WithRedactedSecrets masks the fields tagged with `secret:"true"` in the output of Marshal: strings are replaced
+// by "******" and other types are set to their zero value.
+func WithRedactedSecrets() Option {
+	return optionFunc(func(g *goConfig) {
+		g.redactSecrets = true
+	})
+}
+
+// WithEnvCascade sets the environment variable holding the profile and the suffixes of the .env files loaded
+// by LoadEnvCascade, "{env}" being replaced by the profile. The defaults are APP_ENV and
+// "", ".{env}", ".local", ".{env}.local". An empty variable or no suffixes keep the defaults.
+func WithEnvCascade(envVar string, suffixes ...string) Option {
+	return optionFunc(func(g *goConfig) {
+		if envVar != "" {
+			g.envCascadeVar = envVar
+		}
+
+		if len(suffixes) > 0 {
+			g.envCascade = suffixes
+		}
+	})
+}
+
+// WithEncodedValues decodes the string fields tagged with `encoding:"base64"` or `encoding:"hex"` after parsing,
+// e.g. secrets injected base64 encoded. The values are decoded before the defaults are applied,
+// an invalid value returns an error wrapping ErrConvertingValue.
+func WithEncodedValues() Option {
+	return optionFunc(func(g *goConfig) {
+		g.encodedValues = true
+	})
+}
+
+// WithYAMLConcat concatenates the YAML files read from several directories, or by ParseConfigDir, and parses them
+// at once instead of deep-merging them, so the anchors defined in a file can be referenced by the aliases of the next
+// ones. The files must not repeat a top-level key nor contain "---" document separators, and parsing fails if any
+// file is not a YAML file.
+func WithYAMLConcat() Option {
+	return optionFunc(func(g *goConfig) {
+		g.concatYAML = true
+	})
+}
+
+// WithValueResolver resolves the ${KEY} references of the configuration files with the resolver, e.g. a lookup in
+// a secret store, falling back to the environment variables when it returns false. The resolver must be safe for
+// concurrent use. It also resolves the references of the .env values expanded with WithEnvFileExpansion.
+func WithValueResolver(resolver func(key string) (string, bool)) Option {
+	return optionFunc(func(g *goConfig) {
+		g.resolver = resolver
+	})
+}
+
+// WithoutEnvSubstitution disables the replacement of the ${VAR} references of the configuration files,
+// so they are parsed verbatim, e.g. when the references are meant for another tool. The .env values are not expanded
+// either, even with WithEnvFileExpansion.
+func WithoutEnvSubstitution() Option {
+	return optionFunc(func(g *goConfig) {
+		g.noSubstitution = true
+	})
+}
+
+// WithEnvBareKeys sets the policy applied to the keys without value of the .env files, like "DEBUG":
+// BareKeyError, the default, rejects them, BareKeyEmpty sets them to an empty string and BareKeyTrue to "true".
+func WithEnvBareKeys(policy BareKeyPolicy) Option {
+	return optionFunc(func(g *goConfig) {
+		g.envBareKeys = policy
+	})
+}
+
+// WithSliceMerge sets the strategy merging the slices of the configuration files read from several directories,
+// by ParseConfigDir or by ParseConfigProfile: SliceReplace, the default, replaces them, SliceAppend appends the
+// elements of the later file and SliceMergeByIndex deep-merges the elements with the same index.
+func WithSliceMerge(strategy SliceMergeStrategy) Option {
+	return optionFunc(func(g *goConfig) {
+		g.sliceMerge = strategy
+	})
+}
+
+// WithEnvOverrides overrides any value of the parsed structure with the environment variable named after its path,
+// prefixed by the prefix, e.g. APP_STORAGE_MASTER_PORT overrides storage.master.port with the prefix "APP".
+// The path is built from the yaml, json or toml tags of the fields, or their names, and the keys of the maps, in upper
+// case and joined with underscores. The values are converted to the type of the field and are applied before the
+// defaults and the validation.
+func WithEnvOverrides(prefix string) Option {
+	return optionFunc(func(g *goConfig) {
+		g.envOverrides = true
+		g.envOverridePrefix = prefix
+	})
+}
+
+// WithStrictPermissions rejects the configuration and .env files readable by their group or by other users, e.g. with
+// mode 0644, as they often hold secrets, with an error wrapping ErrInsecurePermissions. Only the files of the operating
+// system are checked, e.g. not the files of an embedded filesystem.
+func WithStrictPermissions() Option {
+	return optionFunc(func(g *goConfig) {
+		g.strictPermissions = true
+	})
+}
+
+// WithEnvFileExpansion expands the ${VAR} references of the unquoted and double-quoted .env values, resolved against
+// the variables defined earlier and the environment, like the references of the configuration files: the delimiters
+// set with WithEnvDelimiters and the resolver set with WithValueResolver are used, and WithoutEnvSubstitution
+// disables the expansion too. Without it, the values are loaded verbatim.
+func WithEnvFileExpansion() Option {
+	return optionFunc(func(g *goConfig) {
+		g.envExpansion = true
+	})
+}
+
+// WithSourceTracking records the source of every value of the parsed configuration, the file, the environment
+// variable or the default tag setting it, returned by Sources, e.g. to find where a wrong value comes from.
+func WithSourceTracking() Option {
+	return optionFunc(func(g *goConfig) {
+		g.sources = newSourceTracker()
+	})
+}
+
+// WithEnvIncludes loads inline the .env files referenced by the lines like "source .env.shared" of the .env files,
+// relative to the directory of the file sourcing them, like the shell source command. A file sourcing itself,
+// directly or through other files, returns an error wrapping ErrCircularInclude.
+func WithEnvIncludes() Option {
+	return optionFunc(func(g *goConfig) {
+		g.envIncludes = true
+	})
+}
+
+// WithEnvDuplicateKeys sets the policy applied to the keys defined more than once in a .env file:
+// DuplicateKeyLast, the default, keeps the last value, DuplicateKeyFirst the first one and DuplicateKeyError rejects
+// them with an error naming the lines of both definitions.
+func WithEnvDuplicateKeys(policy DuplicateKeyPolicy) Option {
+	return optionFunc(func(g *goConfig) {
+		g.envDuplicateKeys = policy
+	})
+}
+
+// WithHTTPTimeout sets the time allowed to fetch a configuration with ParseConfigURL, 30 seconds by default.
+// A zero or negative timeout is ignored.
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return optionFunc(func(g *goConfig) {
+		if timeout > 0 {
+			g.httpTimeout = timeout
+		}
+	})
+}
+
+// WithTemplate executes the content of the configuration files as a text/template before replacing the environment
+// variables and unmarshalling it, to generate values with conditionals and loops. The templates can call env to get
+// an environment variable, default to fall back on a value when another is empty and split to range over the parts of
+// a string. An invalid template returns an error wrapping ErrTemplate.
+func WithTemplate() Option {
+	return optionFunc(func(g *goConfig) {
+		g.template = true
+	})
+}
+
+// normalizeExtensions normalizes every extension of the list.
+func normalizeExtensions(extensions []string) []string {
+	normalized := make([]string, len(extensions))
+	for i, extension := range extensions {
+		normalized[i] = normalizeExtension(extension)
+	}
+
+	return normalized
+}
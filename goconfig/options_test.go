@@ -0,0 +1,584 @@
+package goconfig_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func createConfigFiles(t testing.TB, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	return dir
+}
+
+func TestWithExcludedExtensions(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.md":    "# App documentation",
+		"app.json":  `{"App": {"name": "JSONName"}}`,
+		"app.yaml":  "App:\n  name: AppName\n",
+		"other.md":  "# Other documentation",
+		"Other.txt": "notes",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrAmbiguousConfig)
+
+	config := goconfig.NewGoConfig(goconfig.WithExcludedExtensions("go", ".JSON"))
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "other", dir)
+	assert.ErrorIs(t, err, goconfig.ErrAmbiguousConfig)
+
+	config = goconfig.NewGoConfig(goconfig.WithExcludedExtensions("txt", ".MD"))
+	err = config.ParseConfig(&yamlCfg, "other", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestParseConfigSuccessLooksUpNameBeforeScanning(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.md":     "# App documentation",
+		"app.yaml":   "App:\n  name: AppName\n",
+		"Other.YAML": "App:\n  name: OtherName\n",
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "other", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "OtherName", yamlCfg.App.Name)
+}
+
+func TestWithAllowedExtensions(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.txt":  "notes",
+		"app.yaml": "App:\n  name: AppName\n",
+	})
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig(goconfig.WithAllowedExtensions("yaml", "json"))
+	err := config.ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	config = goconfig.NewGoConfig(goconfig.WithAllowedExtensions("json"))
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestWithCaseSensitiveMatch(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"App.yaml": "App:\n  name: TitleCase\n",
+		"APP.yaml": "App:\n  name: UpperCase\n",
+		"app.yaml": "App:\n  name: LowerCase\n",
+	})
+
+	config := goconfig.NewGoConfig(goconfig.WithCaseSensitiveMatch())
+	for name, expected := range map[string]string{"App": "TitleCase", "APP": "UpperCase", "app": "LowerCase"} {
+		var yamlCfg AppConfig
+		err := config.ParseConfig(&yamlCfg, name, dir)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, yamlCfg.App.Name, name)
+	}
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "aPP", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "aPP", dir)
+	assert.ErrorIs(t, err, goconfig.ErrAmbiguousConfig)
+}
+
+func TestWithCaseSensitiveMatchAndAllowedExtensions(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":   "App:\n  name: YAML\n",
+		"app.json":   `{"App": {"name": "JSON"}}`,
+		"other.YAML": "App:\n  name: UpperExtension\n",
+	})
+	config := goconfig.NewGoConfig(goconfig.WithCaseSensitiveMatch(), goconfig.WithAllowedExtensions("yaml", "json"))
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrAmbiguousConfig)
+
+	err = config.ParseConfig(&yamlCfg, "other", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "UpperExtension", yamlCfg.App.Name)
+
+	config = goconfig.NewGoConfig(goconfig.WithCaseSensitiveMatch(), goconfig.WithAllowedExtensions("yaml"))
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "YAML", yamlCfg.App.Name)
+
+	err = config.ParseConfig(&yamlCfg, "APP", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConfigNotFound)
+}
+
+func TestNewGoConfigPanicsWithUnsupportedOption(t *testing.T) {
+	assert.PanicsWithValue(t, "goconfig: unsupported option string", func() {
+		goconfig.NewGoConfig("yaml")
+	})
+}
+
+func TestWithUnmarshallerAndOtherOptions(t *testing.T) {
+	base := t.TempDir()
+	dir := createConfigFiles(t, map[string]string{"app.toml": "[App]\nname = \"TOMLName\"\n"})
+	assert.NoError(t, os.Rename(dir, filepath.Join(base, "settings")))
+
+	type Config struct {
+		App struct {
+			Name     string `toml:"name" validate:"required"`
+			LogLevel string `toml:"log_level" default:"info"`
+		} `toml:"App"`
+	}
+
+	config := goconfig.NewGoConfig(goconfig.WithUnmarshaller(goconfig.UnmarshallTOML), goconfig.WithBaseDir(base),
+		goconfig.WithDefaultDir("settings"), goconfig.WithDefaults(), goconfig.WithValidation())
+
+	var cfg Config
+	err := config.ParseConfig(&cfg, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "TOMLName", cfg.App.Name)
+	assert.Equal(t, "info", cfg.App.LogLevel)
+
+	bare := goconfig.NewGoConfig(goconfig.UnmarshallTOML, goconfig.WithBaseDir(base), goconfig.WithDefaultDir("settings"))
+	cfg = Config{}
+	err = bare.ParseConfig(&cfg, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "TOMLName", cfg.App.Name)
+	assert.Empty(t, cfg.App.LogLevel)
+}
+
+func TestWithStrictUnmarshal(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"app.yaml":   "App:\n  name: AppName\n  verison: 1.0\n",
+		"other.toml": "[App]\nname = \"AppName\"\nverison = \"1.0\"\n",
+		"valid.json": `{"App": {"name": "AppName", "version": "1.0"}}`,
+	})
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal())
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, "verison")
+
+	var tomlCfg TOMLConfig
+	err = config.ParseConfig(&tomlCfg, "other", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, "App.verison")
+
+	err = config.ParseConfig(&yamlCfg, "valid", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+
+	err = config.ParseConfigBytes(&yamlCfg, []byte(""))
+	assert.NoError(t, err)
+}
+
+func TestWithEnvAggregateErrors(t *testing.T) {
+	content := `APP_NAME=TestApp
+APP_VERSION:1.0
+APP_PORT=${UNDEFINED_PORT}
+APP_DEBUG=true
+`
+	createEnvFile(t, content)
+	defer removeEnvFile(t)
+
+	_, err := goconfig.NewGoConfig().ParseEnv()
+	assert.EqualError(t, err, ".env:2: invalid .env format: APP_VERSION:1.0")
+
+	config := goconfig.NewGoConfig(goconfig.WithEnvAggregateErrors(), goconfig.WithEnvFileExpansion())
+	env, err := config.ParseEnv(".env", "missing.env")
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp", "APP_DEBUG": "true"}, env)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+	assert.ErrorIs(t, err, goconfig.ErrOpeningEnvFile)
+	assert.EqualError(t, err, `.env:2: invalid .env format: APP_VERSION:1.0
+.env:3: environment variable not found: UNDEFINED_PORT
+error opening .env file: in missing.env`)
+
+	t.Setenv("APP_NAME", "")
+	t.Setenv("APP_DEBUG", "")
+	err = config.LoadEnv()
+	assert.Error(t, err)
+	assert.Equal(t, "true", os.Getenv("APP_DEBUG"))
+}
+
+func TestWithEnvCommentPrefixes(t *testing.T) {
+	content := `; semicolon comment
+  // indented slash comment
+    # indented hash comment
+APP_NAME=TestApp
+`
+	createEnvFile(t, content)
+	defer removeEnvFile(t)
+
+	_, err := goconfig.NewGoConfig().ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	config := goconfig.NewGoConfig(goconfig.WithEnvCommentPrefixes(";", "//", "#"))
+	env, err := config.ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp"}, env)
+}
+
+func TestWithBaseDir(t *testing.T) {
+	baseDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseDir, "config", "prod"), 0755))
+	err := os.WriteFile(filepath.Join(baseDir, "config", "app.yaml"), []byte("App:\n  name: AppName\n"), 0644)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(baseDir, "config", "prod", "app.yaml"), []byte("App:\n  version: 2.0\n"), 0644)
+	assert.NoError(t, err)
+
+	config := goconfig.NewGoConfig(goconfig.WithBaseDir(baseDir))
+
+	var yamlCfg AppConfig
+	err = config.ParseConfig(&yamlCfg, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	err = config.ParseConfigProfile(&yamlCfg, "app", "", "config", "config/prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+
+	var recursiveCfg AppConfig
+	err = config.ParseConfigRecursive(&recursiveCfg, "app", "config/prod")
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", recursiveCfg.App.Version)
+
+	otherDir := createConfigFiles(t, map[string]string{"app.yaml": "App:\n  name: OtherApp\n"})
+	err = config.ParseConfig(&yamlCfg, "app", otherDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "OtherApp", yamlCfg.App.Name)
+
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestWithDefaultDir(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": "App:\n  name: AppName\n"})
+	config := goconfig.NewGoConfig(goconfig.WithDefaultDir(dir))
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+}
+
+func TestWithDefaultEnvFile(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), "app.env")
+	assert.NoError(t, os.WriteFile(envFile, []byte("APP_NAME=TestApp\n"), 0644))
+	config := goconfig.NewGoConfig(goconfig.WithDefaultEnvFile(envFile))
+
+	env, err := config.ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp"}, env)
+}
+
+func TestWithProfileFromEnv(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{
+		"default.yaml":    "App:\n  name: DefaultApp\n",
+		"production.yaml": "App:\n  name: ProductionApp\n",
+		"app.yaml":        "App:\n  name: AppName\n",
+	})
+	config := goconfig.NewGoConfig(goconfig.WithProfileFromEnv("DEPLOY_ENV", "default"))
+
+	t.Setenv("DEPLOY_ENV", "production")
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "ProductionApp", yamlCfg.App.Name)
+
+	err = config.ParseConfig(&yamlCfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+
+	assert.NoError(t, os.Unsetenv("DEPLOY_ENV"))
+	var defaultCfg AppConfig
+	err = config.ParseConfig(&defaultCfg, "", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "DefaultApp", defaultCfg.App.Name)
+}
+
+func TestWithEnvDelimiters(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	content := `App:
+  name: {{APP_NAME}}-${APP_NAME}
+  version: {{APP_VERSION:-{{FALLBACK_VERSION:-1.0}}}}
+  log_level: "${{APP_NAME}}"
+`
+	dir, _ := createConfigFile(t, content)
+	config := goconfig.NewGoConfig(goconfig.WithEnvDelimiters("{{", "}}"))
+
+	var yamlCfg AppConfig
+	err := config.ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp-${APP_NAME}", yamlCfg.App.Name)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+	assert.Equal(t, "{{APP_NAME}}", yamlCfg.App.LogLevel)
+
+	err = config.ParseConfigBytes(&yamlCfg, []byte("App:\n  name: {{UNDEFINED_VAR}}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+}
+
+func TestWithValueResolver(t *testing.T) {
+	t.Setenv("APP_VERSION", "1.0")
+	secrets := map[string]string{"vault.app-name": "SecretApp", "APP_VERSION": "2.0"}
+	resolver := func(key string) (string, bool) {
+		value, ok := secrets[key]
+		return value, ok
+	}
+	content := `App:
+  name: ${vault.app-name}
+  version: ${APP_VERSION}
+  log_level: ${APP_LEVEL:-info}
+`
+	dir, _ := createConfigFile(t, content)
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithValueResolver(resolver)).ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "SecretApp", yamlCfg.App.Name)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+	assert.Equal(t, "info", yamlCfg.App.LogLevel)
+}
+
+func TestWithoutEnvSubstitution(t *testing.T) {
+	t.Setenv("FOO", "bar")
+	dir, _ := createConfigFile(t, "App:\n  name: ${FOO}\n  version: ${UNDEFINED_VAR}\n")
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithoutEnvSubstitution()).ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "${FOO}", yamlCfg.App.Name)
+	assert.Equal(t, "${UNDEFINED_VAR}", yamlCfg.App.Version)
+}
+
+func TestWithEnvBareKeys(t *testing.T) {
+	createEnvFile(t, "APP_NAME=TestApp\nDEBUG\n  export VERBOSE  \n")
+	defer removeEnvFile(t)
+
+	_, err := goconfig.NewGoConfig().ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	_, err = goconfig.NewGoConfig(goconfig.WithEnvBareKeys(goconfig.BareKeyError)).ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+
+	env, err := goconfig.NewGoConfig(goconfig.WithEnvBareKeys(goconfig.BareKeyEmpty)).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp", "DEBUG": "", "VERBOSE": ""}, env)
+
+	env, err = goconfig.NewGoConfig(goconfig.WithEnvBareKeys(goconfig.BareKeyTrue)).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp", "DEBUG": "true", "VERBOSE": "true"}, env)
+}
+
+func TestWithEnvBareKeysFailInvalidKey(t *testing.T) {
+	createEnvFile(t, "NOT A KEY\n")
+	defer removeEnvFile(t)
+
+	_, err := goconfig.NewGoConfig(goconfig.WithEnvBareKeys(goconfig.BareKeyTrue)).ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInvalidEnvFormat)
+}
+
+func TestWithStrictPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not checked on Windows")
+	}
+
+	dir := createConfigFiles(t, map[string]string{"app.yaml": "App:\n  name: AppName\n"})
+	config := goconfig.NewGoConfig(goconfig.WithStrictPermissions())
+
+	// Every mode is set explicitly, the files being created with the umask applied.
+	var yamlCfg AppConfig
+	for mode, insecure := range map[os.FileMode]bool{0o644: true, 0o640: true, 0o604: true, 0o600: false, 0o400: false} {
+		assert.NoError(t, os.Chmod(filepath.Join(dir, "app.yaml"), mode))
+
+		err := config.ParseConfig(&yamlCfg, "app", dir)
+		if insecure {
+			assert.ErrorIs(t, err, goconfig.ErrInsecurePermissions, mode)
+		} else {
+			assert.NoError(t, err, mode)
+		}
+
+		err = config.ParseConfigFile(&yamlCfg, filepath.Join(dir, "app.yaml"))
+		assert.Equal(t, insecure, errors.Is(err, goconfig.ErrInsecurePermissions), mode)
+	}
+
+	assert.NoError(t, os.Chmod(filepath.Join(dir, "app.yaml"), 0o644))
+	assert.NoError(t, goconfig.NewGoConfig().ParseConfig(&yamlCfg, "app", dir))
+	assert.NoError(t, config.ParseConfigFS(fstest.MapFS{"config/app.yaml": {Data: []byte("App: {}\n"), Mode: 0o644}},
+		&yamlCfg, "app"))
+}
+
+func TestWithStrictPermissionsEnvFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not checked on Windows")
+	}
+
+	createEnvFile(t, "APP_NAME=TestApp\n")
+	defer removeEnvFile(t)
+	// The mode is set explicitly, the file being created with the umask applied.
+	assert.NoError(t, os.Chmod(".env", 0o644))
+	config := goconfig.NewGoConfig(goconfig.WithStrictPermissions())
+
+	_, err := config.ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrInsecurePermissions)
+	assert.ErrorContains(t, err, ".env has mode 0644")
+
+	assert.NoError(t, os.Chmod(".env", 0o600))
+	env, err := config.ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "TestApp"}, env)
+}
+
+func TestWithEnvDuplicateKeys(t *testing.T) {
+	createEnvFile(t, "APP_NAME=First\nDB_HOST=localhost\n\n# override\nAPP_NAME=Second\n")
+	defer removeEnvFile(t)
+
+	env, err := goconfig.NewGoConfig().ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "Second", "DB_HOST": "localhost"}, env)
+
+	env, err = goconfig.NewGoConfig(goconfig.WithEnvDuplicateKeys(goconfig.DuplicateKeyLast)).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "Second", "DB_HOST": "localhost"}, env)
+
+	env, err = goconfig.NewGoConfig(goconfig.WithEnvDuplicateKeys(goconfig.DuplicateKeyFirst)).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "First", "DB_HOST": "localhost"}, env)
+
+	_, err = goconfig.NewGoConfig(goconfig.WithEnvDuplicateKeys(goconfig.DuplicateKeyError)).ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrDuplicateEnvKey)
+	assert.EqualError(t, err, ".env:5: duplicate .env key: APP_NAME is defined on lines 1 and 5")
+}
+
+func TestWithEnvDuplicateKeysAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.env"), filepath.Join(dir, "second.env")
+	assert.NoError(t, os.WriteFile(first, []byte("APP_NAME=First\n"), 0644))
+	assert.NoError(t, os.WriteFile(second, []byte("APP_NAME=Second\n"), 0644))
+
+	env, err := goconfig.NewGoConfig(goconfig.WithEnvDuplicateKeys(goconfig.DuplicateKeyError)).ParseEnv(first, second)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"APP_NAME": "Second"}, env)
+}
+
+func TestWithEnvOverrides(t *testing.T) {
+	t.Setenv("MYAPP_STORAGE_MASTER_PORT", "5433")
+	t.Setenv("MYAPP_APP_LOG_LEVEL", "debug")
+	t.Setenv("MYAPP_STORAGE_UNKNOWN_PORT", "1234")
+	t.Setenv("STORAGE_SLAVE_PORT", "6543")
+	dir, _ := createConfigFile(t, `App:
+  name: AppName
+  log_level: info
+storage:
+  master:
+    host: master-pg.localhost
+    port: 5432
+  slave:
+    port: 5432
+`)
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithEnvOverrides("MYAPP")).ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 5433, yamlCfg.Storage["master"].Port)
+	assert.Equal(t, "master-pg.localhost", yamlCfg.Storage["master"].Host)
+	assert.Equal(t, 5432, yamlCfg.Storage["slave"].Port)
+	assert.Equal(t, "debug", yamlCfg.App.LogLevel)
+	assert.Equal(t, "AppName", yamlCfg.App.Name)
+	assert.NotContains(t, yamlCfg.Storage, "unknown")
+
+	yamlCfg = AppConfig{}
+	err = goconfig.NewGoConfig().ParseConfig(&yamlCfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 5432, yamlCfg.Storage["master"].Port)
+	assert.Equal(t, "info", yamlCfg.App.LogLevel)
+}
+
+func TestWithEnvOverridesFailInvalidValue(t *testing.T) {
+	t.Setenv("APP_STORAGE_MASTER_PORT", "not-a-port")
+	dir, _ := createConfigFile(t, "storage:\n  master:\n    port: 5432\n")
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithEnvOverrides("APP_")).ParseConfig(&yamlCfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrConvertingValue)
+	assert.ErrorContains(t, err, "APP_STORAGE_MASTER_PORT")
+}
+
+func TestWithEnvFileExpansion(t *testing.T) {
+	t.Setenv("HOST", "localhost")
+	createEnvFile(t, "BASE_URL=http://${HOST}\nAPI_URL=\"${BASE_URL}/api\"\nRAW_URL='${BASE_URL}/raw'\n")
+	defer removeEnvFile(t)
+
+	env, err := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion()).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BASE_URL": "http://localhost",
+		"API_URL":  "http://localhost/api",
+		"RAW_URL":  "${BASE_URL}/raw",
+	}, env)
+
+	config := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion())
+	err = config.LoadEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost/api", os.Getenv("API_URL"))
+	assert.NoError(t, config.UnloadEnv())
+}
+
+func TestEnvFileExpansionDisabledByDefault(t *testing.T) {
+	createEnvFile(t, "BASE_URL=http://${HOST}\nTEMPLATE=\"${UNDEFINED_VAR}\"\n")
+	defer removeEnvFile(t)
+
+	env, err := goconfig.NewGoConfig().ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"BASE_URL": "http://${HOST}", "TEMPLATE": "${UNDEFINED_VAR}"}, env)
+
+	_, err = goconfig.NewGoConfig(goconfig.WithEnvFileExpansion()).ParseEnv()
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+}
+
+func TestWithEnvFileExpansionUsesInstanceSyntax(t *testing.T) {
+	createEnvFile(t, "BASE_URL=http://{{HOST}}\nAPI_URL={{BASE_URL}}/api\nRAW=${HOST}\n")
+	defer removeEnvFile(t)
+
+	resolver := func(key string) (string, bool) {
+		if key == "HOST" {
+			return "secret-host", true
+		}
+
+		return "", false
+	}
+	env, err := goconfig.NewGoConfig(goconfig.WithEnvFileExpansion(), goconfig.WithEnvDelimiters("{{", "}}"),
+		goconfig.WithValueResolver(resolver)).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"BASE_URL": "http://secret-host",
+		"API_URL":  "http://secret-host/api",
+		"RAW":      "${HOST}",
+	}, env)
+
+	env, err = goconfig.NewGoConfig(goconfig.WithEnvFileExpansion(), goconfig.WithoutEnvSubstitution()).ParseEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "http://{{HOST}}", env["BASE_URL"])
+}
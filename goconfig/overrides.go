@@ -0,0 +1,131 @@
+package goconfig
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// regexOverrideSeparator matches the characters of a key not allowed in an environment variable name.
+var regexOverrideSeparator = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// applyEnvOverrides sets the fields of the structure from the environment variables named after their path, e.g.
+// APP_STORAGE_MASTER_PORT for storage.master.port with the prefix "APP". The name of a field is its yaml, json or toml
+// tag, or its Go name, and map entries are named after their key. Only existing map entries are overridden.
+// Structures that are not pointers to a struct, like maps, are left unchanged. onSet is called with the path and the
+// variable of every value overridden.
+func applyEnvOverrides(structure interface{}, prefix string, onSet func(path, source string)) error {
+	value, err := structValue(structure)
+	if err != nil {
+		return nil
+	}
+
+	return envOverrider{onSet: onSet}.overrideValue(value, overrideKey("", prefix), "")
+}
+
+// envOverrider overrides the values of a structure from the environment variables.
+type envOverrider struct {
+	// onSet is called with the path and the source of every value overridden.
+	onSet func(path, source string)
+}
+
+// overrideValue sets the value from the environment variable named by the key,
+// or walks the fields and map entries it holds, extending the key with their names.
+func (o envOverrider) overrideValue(value reflect.Value, key, path string) error {
+	switch {
+	case value.Kind() == reflect.Pointer && value.Type().Elem().Kind() == reflect.Struct:
+		if value.IsNil() {
+			return nil
+		}
+
+		return o.overrideValue(value.Elem(), key, path)
+	case value.Kind() == reflect.Struct:
+		return o.overrideFields(value, key, path)
+	case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String:
+		return o.overrideMap(value, key, path)
+	case !isOverridable(value.Kind()):
+		return nil
+	}
+
+	raw, ok := os.LookupEnv(key)
+	if !ok || key == "" {
+		return nil
+	}
+
+	if err := setValueFromString(value, raw); err != nil {
+		return fmt.Errorf("%w: %v: %v: %w", ErrConvertingValue, path, key, err)
+	}
+
+	o.onSet(path, sourceEnvPrefix+key)
+
+	return nil
+}
+
+// isOverridable checks if a value of the kind can be set from an environment variable, slices and
+// interfaces are not.
+func isOverridable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		return false
+	default:
+		return true
+	}
+}
+
+// overrideFields overrides the exported fields of the struct.
+func (o envOverrider) overrideFields(value reflect.Value, key, path string) error {
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldKey := overrideKey(key, fieldName(structField))
+		if err := o.overrideValue(value.Field(i), fieldKey, joinPath(path, structField.Name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// overrideMap overrides the entries of the map. Map values are not addressable,
+// so every value is copied, overridden and set back into the map.
+func (o envOverrider) overrideMap(value reflect.Value, key, path string) error {
+	for _, mapKey := range value.MapKeys() {
+		elem := reflect.New(value.Type().Elem()).Elem()
+		elem.Set(value.MapIndex(mapKey))
+		entryPath := fmt.Sprintf("%s[%v]", path, mapKey)
+		if err := o.overrideValue(elem, overrideKey(key, mapKey.String()), entryPath); err != nil {
+			return err
+		}
+
+		value.SetMapIndex(mapKey, elem)
+	}
+
+	return nil
+}
+
+// overrideKey joins the name to the key with an underscore, in upper case and with the characters not allowed in
+// an environment variable name, like dots and dashes, replaced by underscores.
+func overrideKey(key, name string) string {
+	name = strings.Trim(regexOverrideSeparator.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+	if key == "" || name == "" {
+		return key + name
+	}
+
+	return key + "_" + name
+}
+
+// fieldName returns the name of the field in the configuration files: its yaml, json or toml tag, or its Go name.
+func fieldName(structField reflect.StructField) string {
+	for _, tag := range []string{"yaml", "json", "toml"} {
+		if name, _, _ := strings.Cut(structField.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return structField.Name
+}
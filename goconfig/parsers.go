@@ -0,0 +1,98 @@
+package goconfig
+
+import (
+	"slices"
+	"sync"
+)
+
+// parserRegistry stores the unmarshalling functions keyed by file extension, it is safe for concurrent use.
+type parserRegistry struct {
+	mu      sync.RWMutex
+	parsers map[string]func(interface{}, []byte) error
+	// formats holds the built-in format, e.g. "yaml", of the extensions parsed by a built-in parser.
+	formats map[string]string
+}
+
+// newParserRegistry creates a registry with the built-in parsers.
+func newParserRegistry(parsers map[string]func(interface{}, []byte) error) *parserRegistry {
+	formats := make(map[string]string, len(parsers))
+	for extension := range parsers {
+		formats[extension] = extension
+	}
+
+	return &parserRegistry{parsers: parsers, formats: formats}
+}
+
+// get returns the parser registered for the extension.
+func (r *parserRegistry) get(extension string) (func(interface{}, []byte) error, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	parser, ok := r.parsers[normalizeExtension(extension)]
+
+	return parser, ok
+}
+
+// set replaces the built-in parser of the extension by a variant parsing the same format, e.g. a strict parser.
+func (r *parserRegistry) set(extension string, parser func(interface{}, []byte) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.parsers[normalizeExtension(extension)] = parser
+}
+
+// register registers a custom parser for the extension, replacing any parser previously registered.
+func (r *parserRegistry) register(extension string, parser func(interface{}, []byte) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	extension = normalizeExtension(extension)
+	r.parsers[extension] = parser
+	delete(r.formats, extension)
+}
+
+// alias registers the parser of the extension for the alias. It returns false if the extension has no parser.
+func (r *parserRegistry) alias(alias, extension string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alias, extension = normalizeExtension(alias), normalizeExtension(extension)
+	parser, ok := r.parsers[extension]
+	if !ok {
+		return false
+	}
+
+	r.parsers[alias] = parser
+	if format, ok := r.formats[extension]; ok {
+		r.formats[alias] = format
+	} else {
+		delete(r.formats, alias)
+	}
+
+	return true
+}
+
+// format returns the built-in format of the extension, false if it is parsed by a custom parser or not at all.
+func (r *parserRegistry) format(extension string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	format, ok := r.formats[normalizeExtension(extension)]
+
+	return format, ok
+}
+
+// extensions returns the sorted extensions with a registered parser.
+func (r *parserRegistry) extensions() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	extensions := make([]string, 0, len(r.parsers))
+	for extension := range r.parsers {
+		extensions = append(extensions, extension)
+	}
+
+	slices.Sort(extensions)
+
+	return extensions
+}
@@ -0,0 +1,52 @@
+package goconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+)
+
+// insecurePermissions are the permission bits of a file readable by its group or by other users.
+const insecurePermissions fs.FileMode = 0o044
+
+// checkConfigPermissions checks the permissions of the configuration file at the given path of the filesystem, if
+// strict permissions are enabled. Only the files of the operating system are checked, e.g. not embedded files.
+func (g goConfig) checkConfigPermissions(fsys fs.FS, filePath string) error {
+	osFsys, ok := fsys.(osFS)
+	if !g.strictPermissions || !ok {
+		return nil
+	}
+
+	info, err := os.Stat(osFsys.resolve(filePath))
+	if err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrReadingFile, filePath, err)
+	}
+
+	return checkPermissions(filePath, info)
+}
+
+// checkEnvFilePermissions checks the permissions of the open .env file.
+func checkEnvFilePermissions(file *os.File, filePath string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: in %v: %w", ErrOpeningEnvFile, filePath, err)
+	}
+
+	return checkPermissions(filePath, info)
+}
+
+// checkPermissions returns an error wrapping ErrInsecurePermissions if the file is readable by its group or by other
+// users. Windows does not have these permission bits, so files are never rejected there.
+func checkPermissions(filePath string, info fs.FileInfo) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	if perm := info.Mode().Perm(); perm&insecurePermissions != 0 {
+		return fmt.Errorf("%w: %v has mode %04o, it must not be readable by group or others, e.g. 0600",
+			ErrInsecurePermissions, filePath, perm)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package goconfig
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshallProperties unmarshalls the Java properties content into the structure.
+// It is registered for the "properties" extension. Keys are split by dots into nested structs or maps,
+// e.g. app.name=Foo sets the field tagged `yaml:"name"` of the field tagged `yaml:"app"`.
+// Lines starting with "#" or "!" are comments and a line ending with a backslash continues on the next line.
+func UnmarshallProperties(structure interface{}, content []byte) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanPropertiesLine(scanner, scanner.Text())
+		if isPropertiesCommentOrEmpty(line) {
+			continue
+		}
+
+		key, value := splitProperty(line)
+		if err := setProperty(root, strings.Split(key, "."), value); err != nil {
+			return fmt.Errorf(formatError, ErrUnmarshalling, err)
+		}
+	}
+
+	return decodeNode(root, structure, scanner.Err())
+}
+
+// decodeNode decodes the node built from the lines of the scanner into the structure, unless reading them failed.
+func decodeNode(root *yaml.Node, structure interface{}, scanErr error) error {
+	if scanErr != nil {
+		return fmt.Errorf(formatError, ErrUnmarshalling, scanErr)
+	}
+
+	if err := root.Decode(structure); err != nil {
+		return fmt.Errorf(formatError, ErrUnmarshalling, err)
+	}
+
+	return nil
+}
+
+// scanPropertiesLine appends the following lines of the scanner to the line while it ends with a continuation
+// backslash. The leading whitespace of the continuation lines is removed.
+func scanPropertiesLine(scanner *bufio.Scanner, line string) string {
+	line = strings.TrimLeft(line, " \t")
+	for hasContinuation(line) && scanner.Scan() {
+		line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t")
+	}
+
+	return strings.TrimSuffix(line, `\`)
+}
+
+// hasContinuation checks if the line ends with an odd number of backslashes.
+func hasContinuation(line string) bool {
+	trailing := len(line) - len(strings.TrimRight(line, `\`))
+	return trailing%2 == 1
+}
+
+// isPropertiesCommentOrEmpty checks if a properties line is a comment or empty.
+func isPropertiesCommentOrEmpty(line string) bool {
+	return line == "" || line[0] == '#' || line[0] == '!'
+}
+
+// splitProperty splits a properties line into its key and value, separated by the first "=" or ":".
+func splitProperty(line string) (string, string) {
+	index := strings.IndexAny(line, "=:")
+	if index < 0 {
+		return strings.TrimSpace(line), ""
+	}
+
+	return strings.TrimSpace(line[:index]), strings.TrimLeft(line[index+1:], " \t")
+}
+
+// setProperty sets the value at the path of keys, creating the intermediate mappings.
+// A key defined twice keeps its last value.
+func setProperty(node *yaml.Node, keys []string, value string) error {
+	for i, key := range keys {
+		child := mappingValue(node, key)
+		last := i == len(keys)-1
+		switch {
+		case child == nil && last:
+			appendMappingEntry(node, key, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+		case child == nil:
+			child = &yaml.Node{Kind: yaml.MappingNode}
+			appendMappingEntry(node, key, child)
+		case last && child.Kind == yaml.ScalarNode:
+			child.Value = value
+		case last || child.Kind != yaml.MappingNode:
+			return fmt.Errorf("key %v is defined both as a value and as a section", strings.Join(keys[:i+1], "."))
+		}
+
+		node = child
+	}
+
+	return nil
+}
+
+// mappingValue returns the value of the key in the mapping node, or nil if it is absent.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// appendMappingEntry appends the key and its value to the mapping node.
+func appendMappingEntry(node *yaml.Node, key string, value *yaml.Node) {
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
@@ -0,0 +1,72 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type PropertiesConfig struct {
+	App struct {
+		Name        string `yaml:"name"`
+		Port        int    `yaml:"port"`
+		Description string `yaml:"description"`
+		Debug       bool   `yaml:"debug"`
+	} `yaml:"app"`
+	Storage map[string]Storage `yaml:"storage"`
+}
+
+func TestUnmarshallPropertiesSuccess(t *testing.T) {
+	content := `# Application
+! another comment
+app.name=Foo
+app.port = 8080
+app.debug: true
+app.description=A long \
+    description
+storage.master.host=master-pg.localhost
+storage.master.port=5432
+storage.slave.host=slave-pg.localhost
+app.name=Bar
+`
+
+	var cfg PropertiesConfig
+	err := goconfig.UnmarshallProperties(&cfg, []byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, "Bar", cfg.App.Name)
+	assert.Equal(t, 8080, cfg.App.Port)
+	assert.True(t, cfg.App.Debug)
+	assert.Equal(t, "A long description", cfg.App.Description)
+	assert.Equal(t, "master-pg.localhost", cfg.Storage["master"].Host)
+	assert.Equal(t, 5432, cfg.Storage["master"].Port)
+	assert.Equal(t, "slave-pg.localhost", cfg.Storage["slave"].Host)
+}
+
+func TestUnmarshallPropertiesFailValueAndSection(t *testing.T) {
+	var cfg PropertiesConfig
+	err := goconfig.UnmarshallProperties(&cfg, []byte("app=Foo\napp.name=Bar\n"))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.ErrorContains(t, err, "key app is defined both as a value and as a section")
+}
+
+func TestUnmarshallPropertiesFailInvalidValue(t *testing.T) {
+	var cfg PropertiesConfig
+	err := goconfig.UnmarshallProperties(&cfg, []byte("app.port=eighty\n"))
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
+
+func TestParseConfigSuccessProperties(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.properties"), []byte("app.name=${APP_NAME}\napp.port=8080\n"), 0644)
+	assert.NoError(t, err)
+
+	var cfg PropertiesConfig
+	err = goconfig.NewGoConfig().ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", cfg.App.Name)
+	assert.Equal(t, 8080, cfg.App.Port)
+}
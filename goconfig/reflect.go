@@ -0,0 +1,197 @@
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldFunc is called by walkFields for every exported field of a struct, with the path of the field.
+type fieldFunc func(field reflect.Value, structField reflect.StructField, path string) error
+
+// structValue returns the struct pointed by structure.
+func structValue(structure interface{}) (reflect.Value, error) {
+	value := reflect.ValueOf(structure)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+	}
+
+	return value.Elem(), nil
+}
+
+// checkStructure checks the structure is a non-nil pointer to a struct or a map, the supported targets of parsing.
+func checkStructure(structure interface{}) error {
+	value := reflect.ValueOf(structure)
+	if value.Kind() != reflect.Pointer || value.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+	}
+
+	if kind := value.Elem().Kind(); kind != reflect.Struct && kind != reflect.Map {
+		return fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+	}
+
+	return nil
+}
+
+// walkFields calls fn for every exported field of the struct and then walks the structs nested in the field.
+// The path of a field is its name joined to the path of its parent with a dot, e.g. "App.Name",
+// map entries and slice elements are identified by their key or index, e.g. "Storage[master].Host".
+func walkFields(value reflect.Value, path string, fn fieldFunc) error {
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		field, fieldPath := value.Field(i), joinPath(path, structField.Name)
+		if err := fn(field, structField, fieldPath); err != nil {
+			return err
+		}
+
+		if err := walkNested(field, fieldPath, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkNested walks the structs held by the value, through pointers, maps, slices and arrays.
+func walkNested(value reflect.Value, path string, fn fieldFunc) error {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			return nil
+		}
+
+		return walkNested(value.Elem(), path, fn)
+	case reflect.Struct:
+		return walkFields(value, path, fn)
+	case reflect.Map:
+		return walkMap(value, path, fn)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := walkNested(value.Index(i), fmt.Sprintf("%s[%d]", path, i), fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkMap walks the structs held by the values of the map, in the order of their keys.
+// Map values are not addressable, so every value is copied, walked and set back into the map.
+func walkMap(value reflect.Value, path string, fn fieldFunc) error {
+	if !isWalkable(value.Type().Elem()) {
+		return nil
+	}
+
+	keys := value.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	})
+
+	for _, key := range keys {
+		elem := reflect.New(value.Type().Elem()).Elem()
+		elem.Set(value.MapIndex(key))
+		if err := walkNested(elem, fmt.Sprintf("%s[%v]", path, key), fn); err != nil {
+			return err
+		}
+
+		value.SetMapIndex(key, elem)
+	}
+
+	return nil
+}
+
+// isWalkable checks if values of the type can hold structs to walk.
+func isWalkable(valueType reflect.Type) bool {
+	switch valueType.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return isWalkable(valueType.Elem())
+	case reflect.Struct:
+		return true
+	default:
+		return false
+	}
+}
+
+// joinPath joins a field name to the path of its parent.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// setValueFromString converts the raw string to the type of the value and sets it.
+// Supported types are string, bool, integers, floats, time.Duration and pointers to them.
+func setValueFromString(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+
+		return setValueFromString(value.Elem(), raw)
+	}
+
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(duration))
+
+		return nil
+	}
+
+	return setScalarFromString(value, raw)
+}
+
+// setScalarFromString converts the raw string to the kind of the value and sets it.
+func setScalarFromString(value reflect.Value, raw string) error {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported type %v", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package goconfig
+
+import (
+	"maps"
+	"reflect"
+	"sync"
+)
+
+const (
+	// sourceDefault is the source of the values set from their default tag.
+	sourceDefault = "default"
+	// sourceEnvPrefix prefixes the name of the environment variable overriding a value in its source.
+	sourceEnvPrefix = "env:"
+)
+
+// sourceTracker stores the source of every value of the last configuration parsed, it is safe for concurrent use.
+type sourceTracker struct {
+	mu      sync.Mutex
+	sources map[string]string
+}
+
+// newSourceTracker creates an empty source tracker.
+func newSourceTracker() *sourceTracker {
+	return &sourceTracker{sources: make(map[string]string)}
+}
+
+// reset forgets the sources of the previous configuration.
+func (s *sourceTracker) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sources = make(map[string]string)
+}
+
+// set stores the source of the value at the path.
+func (s *sourceTracker) set(path, source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sources[path] = source
+}
+
+func (g goConfig) Sources() map[string]string {
+	if g.sources == nil {
+		return map[string]string{}
+	}
+
+	g.sources.mu.Lock()
+	defer g.sources.mu.Unlock()
+
+	return maps.Clone(g.sources.sources)
+}
+
+// resetSources forgets the sources of the previous configuration, if source tracking is enabled.
+func (g goConfig) resetSources() {
+	if g.sources != nil {
+		g.sources.reset()
+	}
+}
+
+// setSource stores the source of the value at the path, if source tracking is enabled.
+func (g goConfig) setSource(path, source string) {
+	if g.sources != nil {
+		g.sources.set(path, source)
+	}
+}
+
+// trackSources runs the step modifying the structure and, if source tracking is enabled, records the source for
+// every value it changed. Values set to the value they already held are not recorded.
+func (g goConfig) trackSources(structure interface{}, source string, step func() error) error {
+	if g.sources == nil {
+		return step()
+	}
+
+	before := leafValues(structure)
+	if err := step(); err != nil {
+		return err
+	}
+
+	for path, value := range leafValues(structure) {
+		previous, ok := before[path]
+		if ok && !reflect.DeepEqual(previous, value) || !ok && !reflect.ValueOf(value).IsZero() {
+			g.sources.set(path, source)
+		}
+	}
+
+	return nil
+}
+
+// leafValues returns the values of the fields of the struct pointed by structure, keyed by path, that do not hold
+// other structs. Structures that are not pointers to a struct, like maps, have no leaf values.
+func leafValues(structure interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	value, err := structValue(structure)
+	if err != nil {
+		return values
+	}
+
+	_ = walkFields(value, "", func(field reflect.Value, _ reflect.StructField, path string) error {
+		if !isWalkable(field.Type()) || field.Type() == timeType {
+			values[path] = field.Interface()
+		}
+
+		return nil
+	})
+
+	return values
+}
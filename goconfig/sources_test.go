@@ -0,0 +1,56 @@
+package goconfig_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type SourcesConfig struct {
+	App struct {
+		Name     string `yaml:"name"`
+		LogLevel string `yaml:"log_level" default:"info"`
+	} `yaml:"app"`
+	Storage map[string]Storage `yaml:"storage"`
+}
+
+func TestSourcesSuccess(t *testing.T) {
+	t.Setenv("APP_STORAGE_MASTER_PORT", "5433")
+	base := createConfigFiles(t, map[string]string{
+		"app.yaml": "app:\n  name: BaseApp\nstorage:\n  master:\n    host: master-pg.localhost\n    port: 5432\n",
+	})
+	override := createConfigFiles(t, map[string]string{"app.yaml": "app:\n  name: OverrideApp\n"})
+
+	config := goconfig.NewGoConfig(goconfig.WithSourceTracking(), goconfig.WithEnvOverrides("APP"),
+		goconfig.WithDefaults())
+
+	var cfg SourcesConfig
+	err := config.ParseConfig(&cfg, "app", base, override)
+	assert.NoError(t, err)
+	assert.Equal(t, 5433, cfg.Storage["master"].Port)
+	assert.Equal(t, map[string]string{
+		"App.Name":             filepath.Join(override, "app.yaml"),
+		"App.LogLevel":         "default",
+		"Storage[master].Host": filepath.Join(base, "app.yaml"),
+		"Storage[master].Port": "env:APP_STORAGE_MASTER_PORT",
+	}, config.Sources())
+
+	err = config.ParseConfig(&SourcesConfig{}, "app", override)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"App.Name":     filepath.Join(override, "app.yaml"),
+		"App.LogLevel": "default",
+	}, config.Sources())
+}
+
+func TestSourcesEmptyWithoutTracking(t *testing.T) {
+	dir := createConfigFiles(t, map[string]string{"app.yaml": "app:\n  name: BaseApp\n"})
+	config := goconfig.NewGoConfig()
+
+	var cfg SourcesConfig
+	err := config.ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Empty(t, config.Sources())
+}
@@ -0,0 +1,206 @@
+package goconfig
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxEnvDepth is the maximum nesting level of the references in the default values of environment variables.
+const maxEnvDepth = 10
+
+// defaultEnvSyntax is the syntax of the references to environment variables, e.g. ${ENV_VAR}.
+var defaultEnvSyntax = newEnvSyntax("${", "}")
+
+// envSyntax is the syntax of the references to environment variables, delimited by open and close.
+type envSyntax struct {
+	open    string
+	close   string
+	pattern *regexp.Regexp
+}
+
+// newEnvSyntax creates the syntax of the references delimited by opening and closing, e.g. "{{" and "}}".
+// The pattern matches the start of a reference up to the end of the variable name and its optional type,
+// optionally escaped with a "$".
+func newEnvSyntax(opening, closing string) envSyntax {
+	pattern := `\$?` + regexp.QuoteMeta(opening) + `([\w.-]+)(?::(int|uint|float|bool|duration))?(` +
+		regexp.QuoteMeta(closing) + `|:-)`
+
+	return envSyntax{open: opening, close: closing, pattern: regexp.MustCompile(pattern)}
+}
+
+// replace replaces the environment variables in the content using the format ${ENV_VAR},
+// the values are resolved using lookup.
+// A variable set to an empty string is replaced by an empty string.
+// A default value can be provided using the format ${ENV_VAR:-default}, it is used when the variable is unset or empty.
+// The default value may reference other variables, e.g. ${PRIMARY_DB:-${FALLBACK_DB}}, up to maxEnvDepth levels.
+// A type can follow the variable name, e.g. ${PORT:int} or ${TIMEOUT:duration:-5s}, the value is then validated and
+// normalized, returning an error wrapping ErrConvertingValue if it is not valid for the type.
+// A reference escaped as $${ENV_VAR} is not replaced and is emitted as the literal ${ENV_VAR}.
+// If environment variables are unset and have no default, it returns an error wrapping ErrVariableNotFound
+// listing all of them.
+func (s envSyntax) replace(content string, lookup func(key string) (string, bool)) (string, error) {
+	e := &expansion{syntax: s, lookup: lookup}
+	replaced, err := e.expand(content, 0)
+	if err != nil {
+		return "", err
+	}
+
+	if len(e.missing) > 0 {
+		return "", fmt.Errorf(formatError, ErrVariableNotFound, strings.Join(e.missing, ", "))
+	}
+
+	return replaced, nil
+}
+
+// expansion is the replacement of the environment variables of a content, collecting the unset variables.
+type expansion struct {
+	syntax  envSyntax
+	lookup  func(key string) (string, bool)
+	missing []string
+}
+
+// expand replaces the environment variables in the content, depth being the nesting level of the content
+// in the default values. The references to unset variables are kept and their variables collected.
+func (e *expansion) expand(content string, depth int) (string, error) {
+	if depth > maxEnvDepth {
+		return "", fmt.Errorf("%w: more than %d levels in %v", ErrEnvNestingTooDeep, maxEnvDepth, content)
+	}
+
+	var replaced strings.Builder
+	for {
+		loc := e.syntax.pattern.FindStringSubmatchIndex(content)
+		if loc == nil {
+			replaced.WriteString(content)
+			return replaced.String(), nil
+		}
+
+		replaced.WriteString(content[:loc[0]])
+		end := loc[1]
+		if content[loc[6]:loc[7]] == ":-" {
+			closing := e.syntax.closingIndex(content[end:])
+			if closing < 0 {
+				replaced.WriteString(content[loc[0]:end])
+				content = content[end:]
+				continue
+			}
+
+			end += closing + len(e.syntax.close)
+		}
+
+		reference := envReference{
+			text:   content[loc[0]:end],
+			envVar: content[loc[2]:loc[3]],
+			suffix: content[loc[6] : end-len(e.syntax.close)],
+		}
+		if loc[4] >= 0 {
+			reference.valueType = content[loc[4]:loc[5]]
+		}
+
+		value, err := e.expandReference(reference, depth)
+		if err != nil {
+			return "", err
+		}
+
+		replaced.WriteString(value)
+		content = content[end:]
+	}
+}
+
+// envReference is a reference to an environment variable found in a content.
+type envReference struct {
+	// text is the whole reference, e.g. ${PORT:int:-8080}.
+	text string
+	// envVar is the name of the variable, e.g. PORT.
+	envVar string
+	// valueType is the optional type of the value, e.g. int.
+	valueType string
+	// suffix is the default value with its ":-" prefix, if any, e.g. :-8080.
+	suffix string
+}
+
+// expandReference returns the value of the reference ${ENV_VAR} or ${ENV_VAR:-default}, validated for its type.
+func (e *expansion) expandReference(reference envReference, depth int) (string, error) {
+	if strings.HasPrefix(reference.text, "$"+e.syntax.open) {
+		return reference.text[1:], nil
+	}
+
+	env, ok := e.lookup(reference.envVar)
+	defaultValue, hasDefault := strings.CutPrefix(reference.suffix, ":-")
+	if !ok && !hasDefault {
+		if !slices.Contains(e.missing, reference.envVar) {
+			e.missing = append(e.missing, reference.envVar)
+		}
+
+		return reference.text, nil
+	}
+
+	if !ok || (env == "" && hasDefault) {
+		var err error
+		if env, err = e.expand(defaultValue, depth+1); err != nil {
+			return "", err
+		}
+	}
+
+	return normalizeEnvValue(reference.envVar, env, reference.valueType)
+}
+
+// normalizeEnvValue validates the value of the variable for the type and returns it in its canonical form,
+// e.g. "1m30s" for the duration "90s". Values without type are returned unchanged.
+func normalizeEnvValue(envVar, value, valueType string) (string, error) {
+	var normalized string
+	var err error
+	switch valueType {
+	case "":
+		return value, nil
+	case "int":
+		var parsed int64
+		parsed, err = strconv.ParseInt(value, 10, 64)
+		normalized = strconv.FormatInt(parsed, 10)
+	case "uint":
+		var parsed uint64
+		parsed, err = strconv.ParseUint(value, 10, 64)
+		normalized = strconv.FormatUint(parsed, 10)
+	case "float":
+		var parsed float64
+		parsed, err = strconv.ParseFloat(value, 64)
+		normalized = strconv.FormatFloat(parsed, 'g', -1, 64)
+	case "bool":
+		var parsed bool
+		parsed, err = strconv.ParseBool(value)
+		normalized = strconv.FormatBool(parsed)
+	case "duration":
+		var parsed time.Duration
+		parsed, err = time.ParseDuration(value)
+		normalized = parsed.String()
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v: %q is not a valid %v", ErrConvertingValue, envVar, value, valueType)
+	}
+
+	return normalized, nil
+}
+
+// closingIndex returns the index of the delimiter closing a reference in the content, skipping the nested
+// references, or -1 if the reference is not closed.
+func (s envSyntax) closingIndex(content string) int {
+	depth := 0
+	for i := 0; i < len(content); i++ {
+		switch {
+		case strings.HasPrefix(content[i:], s.open):
+			depth++
+			i += len(s.open) - 1
+		case strings.HasPrefix(content[i:], s.close) && depth == 0:
+			return i
+		case strings.HasPrefix(content[i:], s.close):
+			depth--
+			i += len(s.close) - 1
+		}
+	}
+
+	return -1
+}
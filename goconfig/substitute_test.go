@@ -0,0 +1,62 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+// discard is an unmarshalling function ignoring the content, so only the substitution is measured.
+func discard(interface{}, []byte) error {
+	return nil
+}
+
+var substitutionContent = []byte(`App:
+  name: ${APP_NAME}
+  version: ${APP_VERSION:-1.0}
+  log_level: {{APP_LEVEL:-info}}
+`)
+
+func BenchmarkSubstitutionDefaultDelimiters(b *testing.B) {
+	b.Setenv("APP_NAME", "BenchApp")
+	config := goconfig.NewGoConfig(goconfig.WithUnmarshaller(discard))
+
+	var cfg AppConfig
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := config.ParseConfigBytes(&cfg, substitutionContent); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSubstitutionCustomDelimiters(b *testing.B) {
+	b.Setenv("APP_NAME", "BenchApp")
+	config := goconfig.NewGoConfig(goconfig.WithUnmarshaller(discard), goconfig.WithEnvDelimiters("{{", "}}"))
+
+	var cfg AppConfig
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := config.ParseConfigBytes(&cfg, substitutionContent); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestSubstitutionCustomDelimitersDoNotRecompile(t *testing.T) {
+	t.Setenv("APP_NAME", "BenchApp")
+	defaultConfig := goconfig.NewGoConfig(goconfig.WithUnmarshaller(discard))
+	customConfig := goconfig.NewGoConfig(goconfig.WithUnmarshaller(discard), goconfig.WithEnvDelimiters("{{", "}}"))
+	content := []byte("name: ${APP_NAME}\nother: {{APP_NAME}}\n")
+
+	var cfg AppConfig
+	defaultAllocs := testing.AllocsPerRun(100, func() {
+		_ = defaultConfig.ParseConfigBytes(&cfg, content)
+	})
+	customAllocs := testing.AllocsPerRun(100, func() {
+		_ = customConfig.ParseConfigBytes(&cfg, content)
+	})
+
+	assert.InDelta(t, defaultAllocs, customAllocs, 2)
+}
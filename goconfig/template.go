@@ -0,0 +1,52 @@
+package goconfig
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// executeTemplate executes the content as a text/template with the functions of templateFuncs.
+func (g goConfig) executeTemplate(content []byte) ([]byte, error) {
+	tmpl, err := template.New("config").Option("missingkey=error").Funcs(g.templateFuncs()).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf(formatError, ErrTemplate, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, fmt.Errorf(formatError, ErrTemplate, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// templateFuncs returns the functions available to the configuration templates:
+//   - env returns the environment variable, or an empty string if it is unset, e.g. {{ env "HOST" }}.
+//   - default returns the value, or the default value if it is empty, e.g. {{ env "PORT" | default "5432" }}.
+//   - split splits the string around the separator, e.g. {{ range split (env "HOSTS") "," }}.
+func (g goConfig) templateFuncs() template.FuncMap {
+	lookup := g.logLookup(g.lookupValue)
+
+	return template.FuncMap{
+		"env": func(key string) string {
+			value, _ := lookup(key)
+			return value
+		},
+		"default": func(defaultValue, value string) string {
+			if value == "" {
+				return defaultValue
+			}
+
+			return value
+		},
+		"split": func(s, sep string) []string {
+			if s == "" {
+				return nil
+			}
+
+			return strings.Split(s, sep)
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package goconfig_test
+
+import (
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigTemplateSuccess(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("REPLICAS", "replica-1,replica-2")
+	t.Setenv("DB_USER", "admin")
+	dir, _ := createConfigFile(t, `App:
+  name: TestApp
+{{- if eq (env "APP_ENV") "production" }}
+  log_level: warn
+{{- else }}
+  log_level: debug
+{{- end }}
+storage:
+{{- range split (env "REPLICAS") "," }}
+  {{ . }}:
+    host: {{ . }}.db.local
+    port: {{ env "DB_PORT" | default "5432" }}
+    user: ${DB_USER}
+{{- end }}
+`)
+
+	var cfg AppConfig
+	err := goconfig.NewGoConfig(goconfig.WithTemplate()).ParseConfig(&cfg, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", cfg.App.LogLevel)
+	assert.Equal(t, map[string]Storage{
+		"replica-1": {Host: "replica-1.db.local", Port: 5432, User: "admin"},
+		"replica-2": {Host: "replica-2.db.local", Port: 5432, User: "admin"},
+	}, cfg.Storage)
+}
+
+func TestParseConfigTemplateFail(t *testing.T) {
+	config := goconfig.NewGoConfig(goconfig.WithTemplate())
+
+	var cfg AppConfig
+	err := config.ParseConfigBytes(&cfg, []byte("App:\n  name: {{ if }}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrTemplate)
+
+	err = config.ParseConfigBytes(&cfg, []byte("App:\n  name: {{ .Name }}\n"))
+	assert.ErrorIs(t, err, goconfig.ErrTemplate)
+
+	err = goconfig.NewGoConfig().ParseConfigBytes(&cfg, []byte("App:\n  name: '{{ env \"APP_NAME\" }}'\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{{ env "APP_NAME" }}`, cfg.App.Name)
+}
@@ -0,0 +1,144 @@
+package goconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalError is the error returned when a configuration file cannot be unmarshalled, e.g. for a syntax error.
+// It carries the file, its format and, when the parser reports it, the position of the failure, so callers can
+// present it like "config error at app.yaml:4". It wraps the parser error, which wraps ErrUnmarshalling.
+type UnmarshalError struct {
+	// File is the path of the file.
+	File string
+	// Format is the extension of the file selecting its parser, e.g. "yaml".
+	Format string
+	// Line is the line of the failure, starting at 1, or 0 if unknown.
+	Line int
+	// Column is the column of the failure, starting at 1, or 0 if unknown.
+	Column int
+	// Err is the error returned by the parser.
+	Err error
+}
+
+// Error returns the error of the parser prefixed with the file and the line of the failure, if known.
+func (e *UnmarshalError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%v: %v", e.File, e.Err)
+	}
+
+	return fmt.Sprintf("%v:%d: %v", e.File, e.Line, e.Err)
+}
+
+// Unwrap returns the error of the parser.
+func (e *UnmarshalError) Unwrap() error {
+	return e.Err
+}
+
+// newUnmarshalError returns the error of the parser of the file wrapped in an UnmarshalError, with the position
+// reported by the parser. Errors not wrapping ErrUnmarshalling, or already wrapped, are returned unchanged.
+func newUnmarshalError(filePath, extension string, err error) error {
+	var unmarshalErr *UnmarshalError
+	if !errors.Is(err, ErrUnmarshalling) || errors.As(err, &unmarshalErr) {
+		return err
+	}
+
+	line, column := errorPosition(err)
+
+	return &UnmarshalError{File: filePath, Format: normalizeExtension(extension), Line: line, Column: column, Err: err}
+}
+
+// newConcatUnmarshalError returns the error of the parser of the concatenated YAML files wrapped in an
+// UnmarshalError like newUnmarshalError, naming the file holding the line of the failure and its line in the file.
+// If the line is unknown, the error names every file.
+func newConcatUnmarshalError(files []configFile, err error) error {
+	wrapped := newUnmarshalError(files[0].path, files[0].extension, err)
+	unmarshalErr, ok := wrapped.(*UnmarshalError)
+	if !ok {
+		return wrapped
+	}
+
+	if unmarshalErr.Line == 0 {
+		paths := make([]string, len(files))
+		for i, file := range files {
+			paths[i] = file.path
+		}
+
+		unmarshalErr.File = strings.Join(paths, ", ")
+
+		return unmarshalErr
+	}
+
+	// Every file is followed by a newline when concatenated, so it spans its own lines plus one.
+	for _, file := range files {
+		lines := bytes.Count(file.content, []byte("\n")) + 1
+		if unmarshalErr.Line <= lines {
+			unmarshalErr.File = file.path
+			break
+		}
+
+		unmarshalErr.Line -= lines
+	}
+
+	return unmarshalErr
+}
+
+// errorPosition returns the line and column of the failure reported by the error of the YAML, JSON or TOML parser,
+// 0 if unknown. The YAML parser only reports the line, in the messages of its errors, its syntax errors not being
+// typed.
+func errorPosition(err error) (int, int) {
+	var jsonErr *jsonPositionError
+	var tomlErr toml.ParseError
+	var yamlErr *yaml.TypeError
+	switch {
+	case errors.As(err, &jsonErr):
+		return jsonErr.line, jsonErr.column
+	case errors.As(err, &tomlErr):
+		return tomlErr.Position.Line, tomlErr.Position.Col
+	case errors.As(err, &yamlErr) && len(yamlErr.Errors) > 0:
+		return yamlErrorLine(yamlErr.Errors[0]), 0
+	default:
+		return yamlSyntaxLine(err), 0
+	}
+}
+
+// yamlSyntaxLine returns the line of the syntax error of the YAML parser wrapped by the error, or 0 if none.
+func yamlSyntaxLine(err error) int {
+	switch wrapper := err.(type) {
+	case nil:
+		return 0
+	case interface{ Unwrap() error }:
+		return yamlSyntaxLine(wrapper.Unwrap())
+	case interface{ Unwrap() []error }:
+		for _, wrapped := range wrapper.Unwrap() {
+			if line := yamlSyntaxLine(wrapped); line > 0 {
+				return line
+			}
+		}
+
+		return 0
+	default:
+		message, ok := strings.CutPrefix(err.Error(), "yaml: ")
+		if !ok {
+			return 0
+		}
+
+		return yamlErrorLine(message)
+	}
+}
+
+// yamlErrorLine returns the line of a message of the YAML parser, like "line 4: did not find expected key",
+// or 0 if the message does not start with a line.
+func yamlErrorLine(message string) int {
+	var line int
+	if _, err := fmt.Sscanf(message, "line %d:", &line); err != nil {
+		return 0
+	}
+
+	return line
+}
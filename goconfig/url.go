@@ -0,0 +1,67 @@
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// defaultHTTPTimeout is the time allowed to fetch a configuration by ParseConfigURL when no timeout is set.
+const defaultHTTPTimeout = 30 * time.Second
+
+// contentTypeExtensions are the extensions of the parsers of the configurations served with a content type.
+var contentTypeExtensions = map[string]string{
+	"application/json":       "json",
+	"application/yaml":       "yaml",
+	"application/x-yaml":     "yaml",
+	"text/yaml":              "yaml",
+	"text/x-yaml":            "yaml",
+	"application/toml":       "toml",
+	"text/x-java-properties": "properties",
+	"text/x-ini":             "ini",
+	"application/json5":      "json5",
+	"application/x-json5":    "json5",
+}
+
+func (g goConfig) ParseConfigURL(ctx context.Context, structure interface{}, rawURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, g.httpTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrFetchingConfig, rawURL, err)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v: %w", ErrFetchingConfig, rawURL, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%w: %v: unexpected status %v", ErrFetchingConfig, rawURL, response.Status)
+	}
+
+	return g.parseReader(structure, response.Body, urlExtension(response.Header.Get("Content-Type"), request.URL))
+}
+
+// urlExtension returns the extension of the parser of a configuration fetched from the URL: the one of its content
+// type, if known, or the extension of the URL path, falling back to YAML.
+func urlExtension(contentType string, u *url.URL) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if extension, ok := contentTypeExtensions[mediaType]; ok {
+			return extension
+		}
+	}
+
+	if extension := strings.TrimPrefix(path.Ext(u.Path), "."); extension != "" {
+		return extension
+	}
+
+	return defaultExtension
+}
@@ -0,0 +1,86 @@
+package goconfig_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigURLSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/app":
+			w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+			_, _ = w.Write([]byte("App:\n  name: ${APP_NAME}\n  version: 1.0.0\n"))
+		case "/app.json":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(`{"app": {"name": "JSONApp"}}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"app": {"name": "TypedApp"}}`))
+		}
+	}))
+	defer server.Close()
+
+	config := goconfig.NewGoConfig()
+
+	var yamlCfg AppConfig
+	err := config.ParseConfigURL(context.Background(), &yamlCfg, server.URL+"/app")
+	assert.NoError(t, err)
+	assert.Equal(t, "TestApp", yamlCfg.App.Name)
+	assert.Equal(t, "1.0.0", yamlCfg.App.Version)
+
+	var jsonCfg JSONConfig
+	err = config.ParseConfigURL(context.Background(), &jsonCfg, server.URL+"/app.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "JSONApp", jsonCfg.App.Name)
+
+	err = config.ParseConfigURL(context.Background(), &jsonCfg, server.URL+"/config")
+	assert.NoError(t, err)
+	assert.Equal(t, "TypedApp", jsonCfg.App.Name)
+}
+
+func TestParseConfigURLFailStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigURL(context.Background(), &yamlCfg, server.URL+"/app.yaml")
+	assert.ErrorIs(t, err, goconfig.ErrFetchingConfig)
+	assert.ErrorContains(t, err, "404 Not Found")
+}
+
+func TestParseConfigURLFailTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig(goconfig.WithHTTPTimeout(50 * time.Millisecond))
+	err := config.ParseConfigURL(context.Background(), &yamlCfg, server.URL+"/app.yaml")
+	assert.ErrorIs(t, err, goconfig.ErrFetchingConfig)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = goconfig.NewGoConfig().ParseConfigURL(ctx, &yamlCfg, server.URL+"/app.yaml")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseConfigURLFailInvalidURL(t *testing.T) {
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().ParseConfigURL(context.Background(), &yamlCfg, "://invalid")
+	assert.ErrorIs(t, err, goconfig.ErrFetchingConfig)
+}
@@ -0,0 +1,70 @@
+package goconfig
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	tagValidate      = "validate"
+	validateRequired = "required"
+)
+
+func (g goConfig) Validate(fileName string, directoryName ...string) error {
+	return g.ValidateConfig(nil, fileName, directoryName...)
+}
+
+func (g goConfig) ValidateConfig(structure interface{}, fileName string, directoryName ...string) error {
+	var fresh interface{} = &map[string]interface{}{}
+	if structure != nil {
+		target := reflect.ValueOf(structure)
+		if target.Kind() != reflect.Pointer || target.IsNil() {
+			return fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+		}
+
+		fresh = reflect.New(target.Type().Elem()).Interface()
+	}
+
+	// The sources of the validated configuration are tracked apart, so Sources keeps describing the last one parsed.
+	if g.sources != nil {
+		g.sources = newSourceTracker()
+	}
+
+	g.validate = true
+
+	return g.ParseConfigFS(g.osFS(), fresh, fileName, directoryName...)
+}
+
+// validateStructure checks the fields tagged with `validate:"required"` are not the zero value.
+// It returns an error wrapping ErrValidation naming every failing field.
+// Structures that are not pointers to a struct, like maps, are not validated.
+func validateStructure(structure interface{}) error {
+	value, err := structValue(structure)
+	if err != nil {
+		return nil
+	}
+
+	var failures []string
+	_ = walkFields(value, "", func(field reflect.Value, structField reflect.StructField, path string) error {
+		if hasValidateRule(structField, validateRequired) && field.IsZero() {
+			failures = append(failures, path+" is required")
+		}
+
+		return nil
+	})
+
+	if len(failures) > 0 {
+		return fmt.Errorf(formatError, ErrValidation, strings.Join(failures, ", "))
+	}
+
+	return nil
+}
+
+// hasValidateRule checks if the comma separated rules of the validate tag of the field contain the rule.
+func hasValidateRule(structField reflect.StructField, rule string) bool {
+	rules, ok := structField.Tag.Lookup(tagValidate)
+
+	return ok && slices.Contains(strings.Split(rules, ","), rule)
+}
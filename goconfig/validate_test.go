@@ -0,0 +1,110 @@
+package goconfig_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+type ValidatedConfig struct {
+	App struct {
+		Name    string `yaml:"name" validate:"required"`
+		Version string `yaml:"version" validate:"required"`
+	} `yaml:"App"`
+	Storage map[string]struct {
+		Host string `yaml:"host" validate:"required"`
+		Port int    `yaml:"port"`
+	} `yaml:"storage"`
+}
+
+func TestWithValidationSuccess(t *testing.T) {
+	dir, _ := createConfigFile(t, `App:
+  name: AppName
+  version: 1.0
+storage:
+  master:
+    host: master-pg.localhost
+`)
+
+	var cfg ValidatedConfig
+	config := goconfig.NewGoConfig(goconfig.WithValidation())
+	err := config.ParseConfig(&cfg, "App", dir)
+	assert.NoError(t, err)
+}
+
+func TestWithValidationFailRequiredFields(t *testing.T) {
+	dir, _ := createConfigFile(t, `App:
+  verison: 1.0
+storage:
+  master:
+    port: 5432
+  slave:
+    host: slave-pg.localhost
+`)
+
+	var cfg ValidatedConfig
+	config := goconfig.NewGoConfig(goconfig.WithValidation())
+	err := config.ParseConfig(&cfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrValidation)
+	assert.EqualError(t, err,
+		"validation failed: App.Name is required, App.Version is required, Storage[master].Host is required")
+}
+
+func TestWithoutValidationRequiredFieldsIgnored(t *testing.T) {
+	var cfg ValidatedConfig
+	config := goconfig.NewGoConfig()
+	err := config.ParseConfigBytes(&cfg, []byte("App:\n  verison: 1.0\n"))
+	assert.NoError(t, err)
+}
+
+func TestWithValidationSkipsMaps(t *testing.T) {
+	var cfg map[string]interface{}
+	config := goconfig.NewGoConfig(goconfig.WithValidation())
+	err := config.ParseConfigBytes(&cfg, []byte("App:\n  name: AppName\n"))
+	assert.NoError(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+	config := goconfig.NewGoConfig()
+
+	cfg := ValidatedConfig{}
+	cfg.App.Name = "Unchanged"
+	err := config.ValidateConfig(&cfg, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrValidation)
+	assert.ErrorContains(t, err, "App.Version is required")
+	assert.Equal(t, "Unchanged", cfg.App.Name)
+
+	assert.NoError(t, config.Validate("App", dir))
+	assert.NoError(t, config.ValidateConfig(nil, "App", dir))
+	assert.ErrorIs(t, config.ValidateConfig(cfg, "App", dir), goconfig.ErrInvalidStructure)
+}
+
+func TestValidateKeepsSources(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	otherDir, _ := createConfigFile(t, "App:\n  name: OtherName\n  version: 1.0.0\n")
+	config := goconfig.NewGoConfig(goconfig.WithSourceTracking())
+
+	var yamlCfg AppConfig
+	assert.NoError(t, config.ParseConfig(&yamlCfg, "App", dir))
+	sources := config.Sources()
+	assert.Equal(t, filepath.Join(dir, file), sources["App.Name"])
+
+	assert.NoError(t, config.Validate("App", otherDir))
+	assert.NoError(t, config.ValidateConfig(&AppConfig{}, "App", otherDir))
+	assert.Equal(t, sources, config.Sources())
+}
+
+func TestValidateFailParse(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: ${VALIDATE_MISSING_VAR}\n")
+	config := goconfig.NewGoConfig()
+
+	err := config.Validate("App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrVariableNotFound)
+
+	dir, _ = createConfigFile(t, "App: [name\n")
+	err = config.ValidateConfig(&ValidatedConfig{}, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+}
@@ -0,0 +1,133 @@
+package goconfig
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Values is a configuration parsed without a structure, its values are accessed with dotted paths
+// like "storage.master.port".
+type Values map[string]interface{}
+
+func (g goConfig) ParseValues(configName string, directoryName ...string) (Values, error) {
+	values := make(map[string]interface{})
+	if err := g.ParseConfig(&values, configName, directoryName...); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// InlineValues returns the keys of the configuration not matching any field of the struct pointed by structure, which
+// YAML collects into its field of type map[string]interface{}, or Values, tagged with `yaml:",inline"`.
+// It returns false if the structure is not a pointer to a struct or has no such field.
+func InlineValues(structure interface{}) (Values, bool) {
+	value, err := structValue(structure)
+	if err != nil {
+		return nil, false
+	}
+
+	valuesType := reflect.TypeOf(Values{})
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		_, options, _ := strings.Cut(structField.Tag.Get("yaml"), ",")
+		if structField.IsExported() && slices.Contains(strings.Split(options, ","), "inline") &&
+			structField.Type.ConvertibleTo(valuesType) {
+			return value.Field(i).Convert(valuesType).Interface().(Values), true
+		}
+	}
+
+	return nil, false
+}
+
+// Get returns the value at the dotted path, e.g. "storage.master.port".
+// It returns false if the path is not found.
+func (v Values) Get(path string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(v)
+	for _, key := range strings.Split(path, ".") {
+		values, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = values[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// GetString returns the value at the dotted path as a string, numbers and booleans are formatted.
+// It returns false if the path is not found or the value is a map or a slice.
+func (v Values) GetString(path string) (string, bool) {
+	value, ok := v.Get(path)
+	if !ok {
+		return "", false
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(value), true
+	default:
+		return "", false
+	}
+}
+
+// GetInt returns the value at the dotted path as an int, strings holding an integer are converted.
+// It returns false if the path is not found or the value is not an integer.
+func (v Values) GetInt(path string) (int, bool) {
+	value, ok := v.Get(path)
+	if !ok {
+		return 0, false
+	}
+
+	switch value := value.(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case uint64:
+		if value > math.MaxInt {
+			return 0, false
+		}
+
+		return int(value), true
+	case float64:
+		if value != math.Trunc(value) {
+			return 0, false
+		}
+
+		return int(value), true
+	case string:
+		parsed, err := strconv.Atoi(value)
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// GetBool returns the value at the dotted path as a bool, strings like "true" or "0" are converted.
+// It returns false if the path is not found or the value is not a boolean.
+func (v Values) GetBool(path string) (bool, bool) {
+	value, ok := v.Get(path)
+	if !ok {
+		return false, false
+	}
+
+	switch value := value.(type) {
+	case bool:
+		return value, true
+	case string:
+		parsed, err := strconv.ParseBool(value)
+		return parsed, err == nil
+	default:
+		return false, false
+	}
+}
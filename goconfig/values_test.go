@@ -0,0 +1,146 @@
+package goconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValuesSuccess(t *testing.T) {
+	t.Setenv("APP_NAME", "TestApp")
+	content := `App:
+  name: ${APP_NAME}
+  version: 1.5
+  debug: "true"
+storage:
+  master:
+    port: 5432
+    replicas: [a, b]
+`
+	dir, _ := createConfigFile(t, content)
+
+	values, err := goconfig.NewGoConfig().ParseValues("App", dir)
+	assert.NoError(t, err)
+
+	name, ok := values.GetString("App.name")
+	assert.True(t, ok)
+	assert.Equal(t, "TestApp", name)
+
+	version, ok := values.GetString("App.version")
+	assert.True(t, ok)
+	assert.Equal(t, "1.5", version)
+
+	port, ok := values.GetInt("storage.master.port")
+	assert.True(t, ok)
+	assert.Equal(t, 5432, port)
+
+	debug, ok := values.GetBool("App.debug")
+	assert.True(t, ok)
+	assert.True(t, debug)
+
+	replicas, ok := values.Get("storage.master.replicas")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"a", "b"}, replicas)
+}
+
+func TestParseValuesMissingOrMismatchedPaths(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: TestApp\n  version: 1.5\n")
+
+	values, err := goconfig.NewGoConfig().ParseValues("App", dir)
+	assert.NoError(t, err)
+
+	name, ok := values.GetString("App.missing")
+	assert.False(t, ok)
+	assert.Empty(t, name)
+
+	port, ok := values.GetInt("App.name.port")
+	assert.False(t, ok)
+	assert.Zero(t, port)
+
+	version, ok := values.GetInt("App.version")
+	assert.False(t, ok)
+	assert.Zero(t, version)
+
+	debug, ok := values.GetBool("App.name")
+	assert.False(t, ok)
+	assert.False(t, debug)
+
+	app, ok := values.GetString("App")
+	assert.False(t, ok)
+	assert.Empty(t, app)
+}
+
+func TestParseValuesSuccessTOML(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.toml"), []byte("[storage.master]\nport = 5432\n"), 0644)
+	assert.NoError(t, err)
+
+	values, err := goconfig.NewGoConfig().ParseValues("app", dir)
+	assert.NoError(t, err)
+
+	port, ok := values.GetInt("storage.master.port")
+	assert.True(t, ok)
+	assert.Equal(t, 5432, port)
+}
+
+func TestParseValuesFailNoDirFound(t *testing.T) {
+	values, err := goconfig.NewGoConfig().ParseValues("app", "notfound")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+	assert.Nil(t, values)
+}
+
+func TestInlineValuesSuccess(t *testing.T) {
+	t.Setenv("PLUGIN_TOKEN", "secret")
+	dir := createConfigFiles(t, map[string]string{"app.yaml": `App:
+  name: AppName
+metrics:
+  enabled: true
+  token: ${PLUGIN_TOKEN}
+region: eu-west-1
+`})
+
+	type Config struct {
+		App   App                    `yaml:"App"`
+		Extra map[string]interface{} `yaml:",inline"`
+	}
+
+	var cfg Config
+	err := goconfig.NewGoConfig(goconfig.WithStrictUnmarshal()).ParseConfig(&cfg, "app", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "AppName", cfg.App.Name)
+	assert.NotContains(t, cfg.Extra, "App")
+
+	extra, ok := goconfig.InlineValues(&cfg)
+	assert.True(t, ok)
+	token, _ := extra.GetString("metrics.token")
+	assert.Equal(t, "secret", token)
+	enabled, _ := extra.GetBool("metrics.enabled")
+	assert.True(t, enabled)
+	region, _ := extra.GetString("region")
+	assert.Equal(t, "eu-west-1", region)
+
+	type ValuesConfig struct {
+		App    App             `yaml:"App"`
+		Plugin goconfig.Values `yaml:",inline"`
+	}
+
+	var valuesCfg ValuesConfig
+	err = goconfig.NewGoConfig().ParseConfig(&valuesCfg, "app", dir)
+	assert.NoError(t, err)
+
+	extra, ok = goconfig.InlineValues(&valuesCfg)
+	assert.True(t, ok)
+	assert.Contains(t, extra, "metrics")
+}
+
+func TestInlineValuesFailWithoutInlineField(t *testing.T) {
+	var cfg AppConfig
+	_, ok := goconfig.InlineValues(&cfg)
+	assert.False(t, ok)
+
+	_, ok = goconfig.InlineValues(map[string]interface{}{})
+	assert.False(t, ok)
+}
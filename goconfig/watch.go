@@ -0,0 +1,170 @@
+package goconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is the time to wait after the last change of a file before reloading it,
+// so the multiple writes of an editor only trigger one reload.
+const watchDebounce = 100 * time.Millisecond
+
+func (g goConfig) Watch(ctx context.Context, structure interface{}, fileName string,
+	onChange func(interface{}, error), directoryName ...string) error {
+	target := reflect.ValueOf(structure)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf(formatError, ErrWatchingConfig, err)
+	}
+
+	for _, dir := range g.configDirs(directoryName) {
+		if err := watcher.Add(g.osFS().resolve(dir)); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("%w: %v: %w", ErrOpenDir, dir, err)
+		}
+	}
+
+	fileName = g.configName(fileName)
+	included, err := g.watchIncludes(watcher, fileName, directoryName)
+	if err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	if onChange == nil {
+		onChange = func(interface{}, error) {}
+	}
+
+	go g.watch(ctx, watcher, target.Type().Elem(), fileName, included, onChange, directoryName)
+
+	return nil
+}
+
+// watch parses the configuration into a new value of the structure type when the watcher reports a change of the
+// configuration file or of a file it includes, and passes it to onChange, until the context is cancelled.
+func (g goConfig) watch(ctx context.Context, watcher *fsnotify.Watcher, structureType reflect.Type, fileName string,
+	included map[string]bool, onChange func(interface{}, error), directoryName []string) {
+	defer func() { _ = watcher.Close() }()
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if g.isConfigChange(event, fileName, included) {
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			onChange(nil, fmt.Errorf(formatError, ErrWatchingConfig, err))
+		case <-debounce.C:
+			fresh := reflect.New(structureType)
+			if err := g.parseFresh(fresh.Interface(), fileName, directoryName); err != nil {
+				onChange(nil, err)
+				continue
+			}
+
+			next, err := g.watchIncludes(watcher, fileName, directoryName)
+			if err != nil {
+				onChange(nil, err)
+				continue
+			}
+
+			included = next
+			onChange(fresh.Interface(), nil)
+		}
+	}
+}
+
+// watchIncludes adds the directories of the files included by the configuration file to the watcher and returns
+// the paths of those files. The files that cannot be read are skipped, parsing the configuration reports them.
+func (g goConfig) watchIncludes(watcher *fsnotify.Watcher, fileName string,
+	directoryName []string) (map[string]bool, error) {
+	fsys := g.osFS()
+	included := make(map[string]bool)
+	for _, dir := range g.configDirs(directoryName) {
+		file, err := g.readFS(fsys, fileName, dir)
+		if err != nil {
+			continue
+		}
+
+		files, err := g.expandIncludes(fsys, file, nil)
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files[:len(files)-1] {
+			filePath := filepath.Clean(fsys.resolve(file.path))
+			if err := watcher.Add(filepath.Dir(filePath)); err != nil {
+				return nil, fmt.Errorf("%w: %v: %w", ErrOpenDir, filepath.Dir(filePath), err)
+			}
+
+			included[filePath] = true
+		}
+	}
+
+	return included, nil
+}
+
+func (g goConfig) RefreshAll(structure interface{}, envFiles []string, fileName string,
+	directoryName ...string) error {
+	target := reflect.ValueOf(structure)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvalidStructure, structure)
+	}
+
+	if err := g.LoadEnv(envFiles...); err != nil {
+		return err
+	}
+
+	return g.reload(target, g.configName(fileName), directoryName)
+}
+
+// reload parses the configuration into a new value and sets it into the target only if parsing succeeds,
+// so a parse error never leaves the target half updated.
+func (g goConfig) reload(target reflect.Value, fileName string, directoryName []string) error {
+	fresh := reflect.New(target.Type().Elem())
+	if err := g.parseFresh(fresh.Interface(), fileName, directoryName); err != nil {
+		return err
+	}
+
+	target.Elem().Set(fresh.Elem())
+
+	return nil
+}
+
+// isConfigChange checks if the event is a write, creation or rename of the configuration file
+// or of one of the included files.
+func (g goConfig) isConfigChange(event fsnotify.Event, fileName string, included map[string]bool) bool {
+	if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) {
+		return false
+	}
+
+	if included[filepath.Clean(event.Name)] {
+		return true
+	}
+
+	_, ok := g.matchConfigFile(filepath.Base(event.Name), fileName)
+
+	return ok
+}
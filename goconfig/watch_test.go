@@ -0,0 +1,223 @@
+package goconfig_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jsalonl/go-config/v2/goconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchSuccessReload(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	changes := make(chan error, 10)
+	var current atomic.Pointer[AppConfig]
+	current.Store(&yamlCfg)
+	err := config.Watch(ctx, &yamlCfg, "App", func(value interface{}, err error) {
+		if err == nil {
+			current.Store(value.(*AppConfig))
+		}
+
+		changes <- err
+	}, dir)
+	assert.NoError(t, err)
+
+	for _, name := range []string{"First", "Second", "Reloaded"} {
+		err = os.WriteFile(filepath.Join(dir, file), []byte("App:\n  name: "+name+"\n"), 0644)
+		assert.NoError(t, err)
+	}
+
+	select {
+	case err := <-changes:
+		assert.NoError(t, err)
+		assert.Equal(t, "Reloaded", current.Load().App.Name)
+		assert.Empty(t, yamlCfg.App.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+
+	assert.Len(t, changes, 0)
+}
+
+func TestWatchSuccessConcurrentReads(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	yamlCfg := AppConfig{App: App{Name: "AppName"}}
+	changes := make(chan error, 10)
+	err := goconfig.NewGoConfig().Watch(ctx, &yamlCfg, "App", func(_ interface{}, err error) { changes <- err }, dir)
+	assert.NoError(t, err)
+
+	stop := make(chan struct{})
+	reads := make(chan string)
+	go func() {
+		name := ""
+		for {
+			select {
+			case <-stop:
+				reads <- name
+				return
+			default:
+				name = yamlCfg.App.Name
+			}
+		}
+	}()
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte("App:\n  name: Reloaded\n"), 0644)
+	assert.NoError(t, err)
+
+	select {
+	case err := <-changes:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+
+	close(stop)
+	assert.Equal(t, "AppName", <-reads)
+}
+
+func TestWatchSuccessReloadIncludedFile(t *testing.T) {
+	shared := createConfigFiles(t, map[string]string{"base.yaml": "App:\n  version: 1.0.0\n"})
+	dir := createConfigFiles(t, map[string]string{
+		"App.yaml": "include: " + filepath.Join(shared, "base.yaml") + "\nApp:\n  name: AppName\n",
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan interface{}, 10)
+	err := goconfig.NewGoConfig().Watch(ctx, &AppConfig{}, "App", func(value interface{}, err error) {
+		assert.NoError(t, err)
+		changes <- value
+	}, dir)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(shared, "base.yaml"), []byte("App:\n  version: 2.0.0\n"), 0644)
+	assert.NoError(t, err)
+
+	select {
+	case value := <-changes:
+		assert.Equal(t, App{Name: "AppName", Version: "2.0.0"}, value.(*AppConfig).App)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
+
+func TestWatchFailReloadKeepsStructure(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	yamlCfg := AppConfig{App: App{Name: "AppName"}}
+	changes := make(chan error, 10)
+	err := config.Watch(ctx, &yamlCfg, "App", func(value interface{}, err error) {
+		assert.Nil(t, value)
+		changes <- err
+	}, dir)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte("\tApp: [\n"), 0644)
+	assert.NoError(t, err)
+
+	select {
+	case err := <-changes:
+		assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+		assert.Equal(t, "AppName", yamlCfg.App.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("configuration was not reloaded")
+	}
+}
+
+func TestWatchStopsWhenContextCancelled(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: AppName\n")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var yamlCfg AppConfig
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	changes := make(chan error, 10)
+	err := config.Watch(ctx, &yamlCfg, "App", func(_ interface{}, err error) { changes <- err }, dir)
+	assert.NoError(t, err)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte("App:\n  name: Reloaded\n"), 0644)
+	assert.NoError(t, err)
+
+	select {
+	case <-changes:
+		t.Fatal("configuration was reloaded after cancelling the context")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestWatchFailNoDirFound(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	var yamlCfg AppConfig
+	err := config.Watch(context.Background(), &yamlCfg, "App", nil, "configuration")
+	assert.ErrorIs(t, err, goconfig.ErrOpenDir)
+}
+
+func TestWatchFailInvalidStructure(t *testing.T) {
+	config := goconfig.NewGoConfig()
+	assert.NotNil(t, config)
+
+	err := config.Watch(context.Background(), AppConfig{}, "App", nil, t.TempDir())
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+}
+
+func TestRefreshAllSuccess(t *testing.T) {
+	dir, file := createConfigFile(t, "App:\n  name: ${REFRESH_NAME}\n  version: ${REFRESH_VERSION:-1.0}\n")
+	envFile := filepath.Join(dir, ".env")
+	assert.NoError(t, os.WriteFile(envFile, []byte("REFRESH_NAME=FirstName\n"), 0644))
+
+	config := goconfig.NewGoConfig()
+	defer func() { assert.NoError(t, config.UnloadEnv()) }()
+
+	var yamlCfg AppConfig
+	err := config.RefreshAll(&yamlCfg, []string{envFile}, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "FirstName", yamlCfg.App.Name)
+	assert.Equal(t, "1.0", yamlCfg.App.Version)
+
+	assert.NoError(t, os.WriteFile(envFile, []byte("REFRESH_NAME=SecondName\nREFRESH_VERSION=2.0\n"), 0644))
+	err = config.RefreshAll(&yamlCfg, []string{envFile}, "App", dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "SecondName", yamlCfg.App.Name)
+	assert.Equal(t, "2.0", yamlCfg.App.Version)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte("App: [invalid\n"), 0644))
+	err = config.RefreshAll(&yamlCfg, []string{envFile}, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrUnmarshalling)
+	assert.Equal(t, "SecondName", yamlCfg.App.Name)
+}
+
+func TestRefreshAllFailEnvFile(t *testing.T) {
+	dir, _ := createConfigFile(t, "App:\n  name: AppName\n")
+
+	var yamlCfg AppConfig
+	err := goconfig.NewGoConfig().RefreshAll(&yamlCfg, []string{filepath.Join(dir, "missing.env")}, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrOpeningEnvFile)
+	assert.Empty(t, yamlCfg.App.Name)
+
+	err = goconfig.NewGoConfig().RefreshAll(yamlCfg, nil, "App", dir)
+	assert.ErrorIs(t, err, goconfig.ErrInvalidStructure)
+}